@@ -0,0 +1,32 @@
+package backup
+
+import (
+	"time"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+// Expired returns generations violating the retention policy
+// (config.KeepBackups, config.BackupMaxAge, config.BackupMaxSize).
+// The newest generation is always kept.
+func Expired(runs []*Run) []*Run {
+	expired := []*Run{}
+
+	var total int64
+	for i, run := range runs {
+		total += run.Size()
+		if i == 0 {
+			continue
+		}
+
+		switch {
+		case config.KeepBackups > 0 && i >= config.KeepBackups:
+		case config.BackupMaxAge > 0 && time.Since(run.Time) > config.BackupMaxAge:
+		case config.BackupMaxSize > 0 && total > config.BackupMaxSize:
+		default:
+			continue
+		}
+		expired = append(expired, run)
+	}
+	return expired
+}
@@ -0,0 +1,190 @@
+package backup
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+const manifestName = "manifest.json"
+
+// Run is a backup generation: directory with files replaced by a single updater run
+// and a manifest describing them.
+type Run struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Mode    string    `json:"mode"`
+	Entries []Entry   `json:"entries"`
+
+	dir string
+}
+
+// Entry describes a single replaced mod file.
+// All paths are absolute.
+type Entry struct {
+	ModID      string `json:"modid,omitempty"`
+	Name       string `json:"name"`
+	OldVersion string `json:"oldVersion,omitempty"`
+	OldPath    string `json:"oldPath,omitempty"`    // Location of the previous file, empty if mod was added
+	BackupPath string `json:"backupPath,omitempty"` // Location of the kept previous file, empty if it was removed
+	NewVersion string `json:"newVersion,omitempty"`
+	NewPath    string `json:"newPath,omitempty"` // Location of the installed file, empty if mod was removed
+}
+
+func NewRun(mode string) *Run {
+	now := time.Now()
+	return &Run{
+		ID:   now.Format("2006-01-02_15-04-05"),
+		Time: now,
+		Mode: mode,
+	}
+}
+
+func (r *Run) Add(e Entry) {
+	r.Entries = append(r.Entries, e)
+}
+
+// Dir returns the generation directory, creating it on first use
+func (r *Run) Dir() (string, error) {
+	if r.dir != "" {
+		return r.dir, nil
+	}
+
+	// Runs within the same second get a suffix
+	id := r.ID
+	for n := 1; exists(filepath.Join(config.BackupPath, id)); n++ {
+		id = fmt.Sprintf("%s_%d", r.ID, n)
+	}
+
+	dir := filepath.Join(config.BackupPath, id)
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	r.ID, r.dir = id, dir
+	return dir, nil
+}
+
+// Save writes manifest to the generation directory. Empty runs are not saved.
+func (r *Run) Save() error {
+	if len(r.Entries) == 0 {
+		return nil
+	}
+
+	dir, err := r.Dir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, manifestName), data, 0o644)
+}
+
+// Store moves file or folder into the generation directory. Returns the new location.
+func (r *Run) Store(path string) (string, error) {
+	dir, err := r.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	dst := filepath.Join(dir, name)
+
+	// Same file might be replaced twice within one run
+	for n := 1; exists(dst); n++ {
+		dst = filepath.Join(dir, fmt.Sprintf("%s.%d%s", strings.TrimSuffix(name, ext), n, ext))
+	}
+	return dst, os.Rename(path, dst)
+}
+
+// Size returns total size of the generation directory
+func (r *Run) Size() int64 {
+	var size int64
+	filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// Remove deletes the generation directory
+func (r *Run) Remove() error {
+	if r.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(r.dir)
+}
+
+// Discard removes generation directory of a run without entries.
+// Fails if any file was left in it.
+func (r *Run) Discard() error {
+	if r.dir == "" {
+		return nil
+	}
+	return os.Remove(r.dir)
+}
+
+// Runs returns recorded generations, newest first
+func Runs() ([]*Run, error) {
+	entries, err := os.ReadDir(config.BackupPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []*Run{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(config.BackupPath, e.Name())
+		data, err := os.ReadFile(filepath.Join(dir, manifestName))
+		if os.IsNotExist(err) {
+			// Not a generation directory (e.g. backed up folder mod)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		run := &Run{dir: dir}
+		err = json.Unmarshal(data, run)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		runs = append(runs, run)
+	}
+
+	slices.SortFunc(runs, func(a, b *Run) int { return b.Time.Compare(a.Time) })
+	return runs, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("%s (%s -> %s)", cmp.Or(e.Name, e.ModID), cmp.Or(e.OldVersion, "none"), cmp.Or(e.NewVersion, "removed"))
+}
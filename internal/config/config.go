@@ -1,33 +1,81 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
+	"golang.org/x/mod/semver"
 )
 
 // Flags
 var (
-	ModPath    string
-	Backup     bool
-	BackupPath string
-	DryRun     bool
-	PreRelease bool
-	NoConfirm  bool
-	Ignored    = map[string]struct{}{}
+	ModPath         string   // First mod directory, new mods are downloaded into it
+	ModPaths        []string // All mod directories
+	DataPath        string
+	SkipDisabled    bool
+	Backup          bool
+	BackupPath      string
+	DryRun          bool
+	PreRelease      bool
+	NoConfirm       bool
+	GameVersion     string
+	GamePath        string
+	Jobs            int
+	CachePath       string
+	CacheTTL        time.Duration
+	Refresh         bool
+	Offline         bool
+	APIURL          string
+	Timeout         time.Duration
+	DownloadTimeout time.Duration
+	Retries         = 3
+	KeepBackups     int
+	BackupMaxAge    time.Duration
+	BackupMaxSize   int64
+	Output          = "text"
+	ConfigPath      string
+	Profile         string
+	Ignored         = map[string]struct{}{}
+	Mods            = map[string]ModPolicy{} // Per-mod policies from config file
+	Changelogs      bool
+	Redownload      bool
+	Server          bool
+	SearchTags      []string
+	SearchSide      string
+	SearchSort      = "downloads"
+	SearchLimit     int
 )
 
+const DefaultAPIURL = "https://mods.vintagestory.at"
+
+// Args are the arguments remaining after flags
+var Args []string
+
 // Modes
 var (
-	Version bool
-	Self    bool
-	List    bool
-	Simple  bool
-	Import  string
-	Export  string
+	Version   bool
+	Self      bool
+	List      bool
+	Simple    bool
+	Import    string
+	Export    string
+	Install   bool
+	Uninstall bool
+	Search    string
+	Changelog string
+	Doctor    bool
+	Verify    bool
+	SyncFrom  string
+	Check     bool
+	Rollback  bool
+	Prune     bool
 )
 
 func init() {
@@ -38,12 +86,90 @@ func init() {
 	cfgPath = filepath.Join(cfgPath, "VintagestoryData")
 
 	// Flags
-	pflag.StringVarP(&ModPath, "mod-path", "m", filepath.Join(cfgPath, "Mods"), "path to VS mod directory")
+	pflag.StringVar(&ConfigPath, "config", defaultConfigPath(), "path to config file")
+	pflag.StringVar(&Profile, "profile", "", "config file profile to use")
+	pflag.StringArrayVarP(&ModPaths, "mod-path", "m", []string{filepath.Join(cfgPath, "Mods")}, "path to VS mod directory, can be repeated")
+	pflag.StringVar(&DataPath, "data-path", cfgPath, "path to VS data directory (with clientsettings.json)")
+	pflag.BoolVar(&Server, "server", false, "dedicated server: use mod directories from serverconfig.json in data path, client-only mods are not updated")
+	pflag.BoolVar(&SkipDisabled, "skip-disabled", false, "do not update mods disabled in the game")
 	pflag.BoolVarP(&Backup, "backup", "b", false, "backup mods instead of removing them")
 	pflag.StringVar(&BackupPath, "backup-path", "", "path to VS mod backup directory")
+	pflag.IntVar(&KeepBackups, "keep-backups", 10, "number of backup runs to keep (0 keeps all)")
+	pflag.DurationVar(&BackupMaxAge, "backup-max-age", 0, "remove backup runs older than this, e.g. 720h (0 disables)")
+	pflag.Func("backup-max-size", "remove oldest backup runs above this total size, e.g. 500MB (0 disables)", func(s string) error {
+		size, err := parseSize(s)
+		if err != nil {
+			return err
+		}
+		BackupMaxSize = size
+		return nil
+	})
 	pflag.BoolVarP(&DryRun, "dry-run", "p", false, "run the updater without actually doing anything")
 	pflag.BoolVar(&PreRelease, "pre-release", false, "allow updating to pre-release mod versions (enabled if mod is already pre-release)")
 	pflag.BoolVarP(&NoConfirm, "no-confirm", "y", false, "automatically confirm all update actions")
+	pflag.FuncP("output", "o", "output format of list, check and update check: text, json, ndjson (default text)", func(s string) error {
+		switch s {
+		case "text", "json", "ndjson":
+			Output = s
+			return nil
+		}
+		return fmt.Errorf("invalid output format: %s", s)
+	})
+	pflag.IntVarP(&Jobs, "jobs", "j", 8, "number of mods checked concurrently")
+	pflag.Func("game-version", "only update to releases supporting this game version: 1.20.3 (detected from installation by default)", func(s string) error {
+		v := strings.TrimSpace(s)
+		if v != "" && v[0] != 'v' {
+			v = "v" + v
+		}
+		if !semver.IsValid(v) {
+			return fmt.Errorf("invalid game version: %s", s)
+		}
+		GameVersion = v
+		return nil
+	})
+	pflag.StringVar(&GamePath, "game-path", "", "path to VS installation used to detect game version")
+	pflag.Func("api-url", "ModDB base url (default https://mods.vintagestory.at)", func(s string) error {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid url: %s", s)
+		}
+		APIURL = s
+		return nil
+	})
+	pflag.DurationVar(&Timeout, "timeout", 30*time.Second, "timeout of a single ModDB API request (0 disables)")
+	pflag.DurationVar(&DownloadTimeout, "download-timeout", 10*time.Minute, "timeout of a single mod download (0 disables)")
+	pflag.Func("retries", "number of retries of failed ModDB requests (default 3)", func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid retries: %s", s)
+		}
+		Retries = n
+		return nil
+	})
+	pflag.StringVar(&CachePath, "cache-path", "", "path to ModDB response cache directory")
+	pflag.DurationVar(&CacheTTL, "cache-ttl", 15*time.Minute, "how long cached ModDB responses are used without revalidation")
+	pflag.BoolVar(&Refresh, "refresh", false, "ignore cached ModDB responses")
+	pflag.BoolVar(&Offline, "offline", false, "use only cached ModDB responses (implies --dry-run)")
+	pflag.BoolVar(&Changelogs, "changelogs", false, "show changelogs in the update list")
+	pflag.BoolVar(&Redownload, "redownload", false, "replace modified mods found by --verify with clean copies")
+	pflag.StringSliceVar(&SearchTags, "tags", nil, "only search mods with all of the tags: tag1,tag2,...")
+	pflag.Func("side", "only search mods for the side: client, server, both", func(s string) error {
+		switch s {
+		case "client", "server", "both":
+			SearchSide = s
+			return nil
+		}
+		return fmt.Errorf("invalid side: %s", s)
+	})
+	pflag.Func("sort", "sort search results by: downloads, trending, updated (default downloads)", func(s string) error {
+		switch s {
+		case "downloads", "trending", "updated":
+			SearchSort = s
+			return nil
+		}
+		return fmt.Errorf("invalid sort: %s", s)
+	})
+	pflag.IntVar(&SearchLimit, "limit", 20, "maximum number of search results")
 	pflag.FuncP("ignore", "x", "disable updates: modID1,modID2,...", func(s string) error {
 		for modID := range strings.SplitSeq(s, ",") {
 			modID = strings.TrimSpace(modID)
@@ -61,20 +187,94 @@ func init() {
 	pflag.BoolVarP(&Simple, "simple", "s", false, "simple update mode")
 	pflag.StringVarP(&Import, "import", "i", "", "import mod list")
 	pflag.StringVarP(&Export, "export", "e", "", "export mod list")
+	pflag.BoolVar(&Install, "install", false, "install mods given as arguments: modid, modid@version, modid@latest or ModDB url")
+	pflag.BoolVar(&Uninstall, "uninstall", false, "uninstall mods given as modid arguments")
+	pflag.StringVar(&Search, "search", "", "search ModDB for mods")
+	pflag.StringVar(&Changelog, "changelog", "", "show changelog of mod releases newer than installed")
+	pflag.BoolVar(&Doctor, "doctor", false, "find mods installed more than once")
+	pflag.BoolVar(&Verify, "verify", false, "compare installed mods with ModDB releases")
+	pflag.StringVar(&SyncFrom, "sync-from", "", "make mods match the server mod set from a lockfile, mod list or mod directory")
+	pflag.BoolVar(&Check, "check", false, "check for updates without changing anything (exit code: 0 up to date, 100 updates available, 1 errors)")
+	pflag.BoolVar(&Rollback, "rollback", false, "restore mods replaced by a previous run")
+	pflag.BoolVar(&Prune, "prune-backups", false, "remove backup runs according to retention policy")
 
 	// Parse flags
 	pflag.Parse()
 
-	// Make sure modpath is absolute path
-	ModPath, err = filepath.Abs(ModPath)
+	Args = pflag.Args()
+
+	// Config file fills in flags missing on the command line
+	err = loadFile()
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, "config:", err)
+		os.Exit(2)
+	}
+
+	if pflag.CommandLine.Changed("data-path") && !pflag.CommandLine.Changed("mod-path") {
+		ModPaths = []string{filepath.Join(DataPath, "Mods")}
 	}
 
+	// Make sure modpaths are absolute paths, listed once
+	dirs, seen := []string{}, map[string]bool{}
+	for _, path := range ModPaths {
+		path, err = filepath.Abs(path)
+		if err != nil {
+			panic(err)
+		}
+
+		// Symlinked aliases point to the same mods
+		key := path
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			key = real
+		}
+		if !seen[key] {
+			seen[key] = true
+			dirs = append(dirs, path)
+		}
+	}
+	ModPaths = dirs
+	ModPath = ModPaths[0]
+
 	if BackupPath == "" {
 		// Set backup path as a sibling of mod path
 		BackupPath = filepath.Join(filepath.Dir(ModPath), "ModBackups")
 	}
+
+	if APIURL == "" {
+		APIURL = DefaultAPIURL
+	}
+
+	if CachePath == "" {
+		// Cache is optional, leave it disabled if there is no cache dir
+		if cacheDir, err := os.UserCacheDir(); err == nil {
+			CachePath = filepath.Join(cacheDir, "VSModUpdater")
+		}
+	}
+
+	if Offline {
+		// Nothing can be downloaded
+		DryRun = true
+	}
+}
+
+var sizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "kib": 1 << 10,
+	"m": 1e6, "mb": 1e6, "mib": 1 << 20,
+	"g": 1e9, "gb": 1e9, "gib": 1 << 30,
+}
+
+// parseSize parses human readable size: 1024, 500MB, 2GiB
+func parseSize(s string) (int64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	num := strings.TrimRight(s, "bgikm")
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	unit, ok := sizeUnits[s[len(num):]]
+	if err != nil || !ok || n < 0 {
+		return 0, fmt.Errorf("invalid size: %s", s)
+	}
+	return int64(n * float64(unit)), nil
 }
 
 var version = "v0.0.0"
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/pflag"
+	"github.com/tailscale/hujson"
+)
+
+// ModPolicy is per-mod update policy
+type ModPolicy struct {
+	Version    string `json:"version,omitempty"`     // Version constraint: 1.4.2, ~1.4, <2.0.0, ...
+	PreRelease *bool  `json:"pre-release,omitempty"` // Overrides --pre-release
+}
+
+// file is the HuJSON config file. Keys of settings and profiles are flag names,
+// except "mods" holding per-mod policies.
+type file struct {
+	Profile  string                    `json:"profile"`  // Used when --profile is not set
+	Settings map[string]any            `json:"settings"` // Shared by all profiles
+	Profiles map[string]map[string]any `json:"profiles"`
+}
+
+// loadFile applies settings of the selected profile to flags not set on the command line
+func loadFile() error {
+	data, err := os.ReadFile(ConfigPath)
+	if os.IsNotExist(err) && !pflag.CommandLine.Changed("config") {
+		if Profile != "" {
+			return fmt.Errorf("profile %s: %s not found", Profile, ConfigPath)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err = hujson.Standardize(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if err != nil {
+		return fmt.Errorf("%s: %w", ConfigPath, err)
+	}
+
+	f := &file{}
+	err = json.Unmarshal(data, f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ConfigPath, err)
+	}
+
+	settings := maps.Clone(f.Settings)
+	if settings == nil {
+		settings = map[string]any{}
+	}
+
+	if Profile == "" {
+		Profile = f.Profile
+	}
+	var profile map[string]any
+	if Profile != "" {
+		var ok bool
+		profile, ok = f.Profiles[Profile]
+		if !ok {
+			return fmt.Errorf("%s: unknown profile %s", ConfigPath, Profile)
+		}
+		maps.Copy(settings, profile)
+	}
+
+	// Profile policies replace shared policies of the same mod
+	for _, s := range []map[string]any{f.Settings, profile} {
+		err = decodeMods(s["mods"])
+		if err != nil {
+			return fmt.Errorf("%s: mods: %w", ConfigPath, err)
+		}
+	}
+	delete(settings, "mods")
+
+	// Sorted for stable error messages
+	for _, name := range slices.Sorted(maps.Keys(settings)) {
+		if name == "config" || name == "profile" {
+			return fmt.Errorf("%s: %s can't be set in config file", ConfigPath, name)
+		}
+
+		flag := pflag.CommandLine.Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("%s: unknown setting %s", ConfigPath, name)
+		}
+
+		// Command line overrides config file
+		if flag.Changed {
+			continue
+		}
+
+		values, ok := settings[name].([]any)
+		if !ok {
+			values = []any{settings[name]}
+		}
+
+		for _, v := range values {
+			err = pflag.CommandLine.Set(name, fileValue(v))
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", ConfigPath, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// decodeMods adds per-mod policies to Mods
+func decodeMods(v any) error {
+	if v == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	mods := map[string]ModPolicy{}
+	err = json.Unmarshal(data, &mods)
+	if err != nil {
+		return err
+	}
+	maps.Copy(Mods, mods)
+	return nil
+}
+
+// fileValue converts JSON value to flag value
+func fileValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "VSModUpdater", "config.json")
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"unicode/utf16"
+)
+
+// Assembly shipped with every game installation, carrying the game version in its resources
+const Assembly = "VintagestoryAPI.dll"
+
+var (
+	ErrNotFound   = errors.New("game installation not found")
+	ErrNoVersion  = errors.New("game version not found")
+	versionRegexp = regexp.MustCompile(`\d+\.\d+\.\d+(-[0-9A-Za-z.]+)?`)
+)
+
+// Find returns the Vintage Story installation directory.
+// If path is empty, VINTAGE_STORY environment variable and standard install locations are searched.
+func Find(path string) (string, error) {
+	if path != "" {
+		dir := findAssembly(path, 3)
+		if dir == "" {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return dir, nil
+	}
+
+	candidates := installPaths()
+	if env := os.Getenv("VINTAGE_STORY"); env != "" {
+		candidates = append([]string{env}, candidates...)
+	}
+
+	for _, candidate := range candidates {
+		if dir := findAssembly(candidate, 3); dir != "" {
+			return dir, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// findAssembly returns the directory containing the game assembly,
+// looking up to depth levels below root (e.g. macOS app bundles).
+func findAssembly(root string, depth int) string {
+	if _, err := os.Stat(filepath.Join(root, Assembly)); err == nil {
+		return root
+	}
+
+	if depth == 0 {
+		return ""
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return ""
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if dir := findAssembly(filepath.Join(root, e.Name()), depth-1); dir != "" {
+			return dir
+		}
+	}
+	return ""
+}
+
+// Version reads the game version from the assembly in the installation directory
+func Version(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, Assembly))
+	if err != nil {
+		return "", err
+	}
+
+	for _, key := range []string{"ProductVersion", "FileVersion"} {
+		value := versionString(data, key)
+		if v := versionRegexp.FindString(value); v != "" {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrNoVersion, dir)
+}
+
+// versionString returns the value of key from the PE version resource (VS_VERSION_INFO).
+// Keys and values are stored as null terminated UTF-16LE strings, aligned to 32 bits.
+func versionString(data []byte, key string) string {
+	needle := utf16le(key + "\x00")
+
+	idx := bytes.Index(data, needle)
+	if idx < 0 {
+		return ""
+	}
+	data = data[idx+len(needle):]
+
+	// Skip padding
+	for len(data) >= 2 && data[0] == 0 && data[1] == 0 {
+		data = data[2:]
+	}
+
+	chars := []uint16{}
+	for len(data) >= 2 {
+		c := binary.LittleEndian.Uint16(data)
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+		data = data[2:]
+	}
+	return string(utf16.Decode(chars))
+}
+
+func utf16le(s string) []byte {
+	chars := utf16.Encode([]rune(s))
+	buf := make([]byte, 0, len(chars)*2)
+	for _, c := range chars {
+		buf = binary.LittleEndian.AppendUint16(buf, c)
+	}
+	return buf
+}
@@ -0,0 +1,16 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func installPaths() []string {
+	home, _ := os.UserHomeDir()
+
+	return []string{
+		"/Applications/Vintage Story.app",
+		filepath.Join(home, "Applications/Vintage Story.app"),
+		filepath.Join(home, "Library/Application Support/Vintagestory"),
+	}
+}
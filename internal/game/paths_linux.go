@@ -0,0 +1,20 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func installPaths() []string {
+	home, _ := os.UserHomeDir()
+	flatpak := "app/at.vintagestory.VintageStory/current/active/files/extra/vintagestory"
+
+	return []string{
+		filepath.Join(home, ".local/share/vintagestory"),
+		filepath.Join(home, ".local/share/flatpak", flatpak),
+		filepath.Join("/var/lib/flatpak", flatpak),
+		"/opt/vintagestory",
+		"/usr/share/vintagestory",
+		"/usr/lib/vintagestory",
+	}
+}
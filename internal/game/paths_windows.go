@@ -0,0 +1,15 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func installPaths() []string {
+	return []string{
+		filepath.Join(os.Getenv("APPDATA"), "Vintagestory"),
+		filepath.Join(os.Getenv("LOCALAPPDATA"), "Vintagestory"),
+		filepath.Join(os.Getenv("ProgramFiles"), "Vintagestory"),
+		filepath.Join(os.Getenv("ProgramFiles(x86)"), "Vintagestory"),
+	}
+}
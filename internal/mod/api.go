@@ -1,6 +1,7 @@
 package mod
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
@@ -10,11 +11,17 @@ import (
 )
 
 var (
-	ErrNoUpdate       = errors.New("no update")
-	ErrNoModID        = errors.New("no modid")
-	ErrInvalidSemVer  = errors.New("is not a valid Semantic Version")
-	ErrPreReleaseSkip = errors.New("skipped pre-release version")
-	ErrUnstableSkip   = errors.New("skipped pre-release game version")
+	ErrNoUpdate        = errors.New("no update")
+	ErrNoModID         = errors.New("no modid")
+	ErrInvalidSemVer   = errors.New("is not a valid Semantic Version")
+	ErrPreReleaseSkip  = errors.New("skipped pre-release version")
+	ErrUnstableSkip    = errors.New("skipped pre-release game version")
+	ErrGameVersionSkip = errors.New("skipped incompatible game version")
+	ErrConstraintSkip  = errors.New("held back by constraint")
+	ErrIncomplete      = errors.New("incomplete download")
+	ErrModIDMismatch   = errors.New("modid mismatch")
+	ErrHashMismatch    = errors.New("hash mismatch")
+	ErrNoRelease       = errors.New("no release found")
 )
 
 type Response struct {
@@ -22,6 +29,30 @@ type Response struct {
 	StatusCode string `json:"statuscode,omitempty"`
 }
 
+// ListResponse is ModDB mod listing
+type ListResponse struct {
+	Mods       []ListedMod `json:"mods"`
+	StatusCode string      `json:"statuscode,omitempty"`
+}
+
+// ListedMod is a mod of the ModDB mod listing, releases are not included
+type ListedMod struct {
+	ModID          int      `json:"modid,omitempty"`
+	AssetID        int      `json:"assetid,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Summary        string   `json:"summary,omitempty"`
+	ModIDStrs      []string `json:"modidstrs,omitempty"`
+	Author         string   `json:"author,omitempty"`
+	Side           string   `json:"side,omitempty"`
+	Type           string   `json:"type,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Downloads      int      `json:"downloads,omitempty"`
+	Follows        int      `json:"follows,omitempty"`
+	TrendingPoints int      `json:"trendingpoints,omitempty"`
+	Comments       int      `json:"comments,omitempty"`
+	LastReleased   string   `json:"lastreleased,omitempty"`
+}
+
 type Release struct {
 	ReleaseID  int      `json:"releaseid,omitempty"`
 	Mainfile   string   `json:"mainfile,omitempty"`
@@ -78,6 +109,10 @@ func NewSemVer(x string) (SemVer, error) {
 	return v, nil
 }
 
+func (v SemVer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
 func (v *SemVer) UnmarshalJSON(data []byte) error {
 	v.string = string(data)
 	v.string = strings.Trim(v.string, `"`)
@@ -108,6 +143,11 @@ func (v SemVer) PreRelease() bool {
 	return semver.Prerelease(v.string) != ""
 }
 
+// MajorMinor returns the major.minor prefix of v (e.g. v1.20)
+func (v SemVer) MajorMinor() string {
+	return semver.MajorMinor(v.string)
+}
+
 func (v SemVer) String() string {
 	v.Sanitize()
 	return v.string
@@ -133,3 +173,32 @@ func IsAllPreRelease(versions []SemVer) bool {
 	}
 	return true
 }
+
+// IsGameCompatible reports whether any of the release tags targets the same
+// major.minor game version as game. Zero game version accepts every release.
+func IsGameCompatible(tags []SemVer, game SemVer) bool {
+	if !game.IsValid() || len(tags) == 0 {
+		return true
+	}
+
+	for _, v := range tags {
+		if v.MajorMinor() == game.MajorMinor() {
+			return true
+		}
+	}
+	return false
+}
+
+// LatestRelease returns the newest release supporting game.
+// Pre-releases are skipped unless allowDev is set.
+func LatestRelease(mod *Mod, game SemVer, allowDev bool) (Release, bool) {
+	for _, rel := range mod.Releases {
+		if !allowDev && rel.ModVersion.PreRelease() {
+			continue
+		}
+		if IsGameCompatible(rel.Tags, game) {
+			return rel, true
+		}
+	}
+	return Release{}, false
+}
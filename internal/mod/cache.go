@@ -0,0 +1,164 @@
+package mod
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+var (
+	ErrNotCached = errors.New("not cached")
+	ErrOffline   = errors.New("offline mode")
+)
+
+// cacheEntry is ModDB API response stored on disk
+type cacheEntry struct {
+	Fetched      time.Time       `json:"fetched"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// cachePath returns cache file of the mod, responses from different servers are kept apart
+func (db *HTTPModDB) cachePath(modID string) string {
+	return filepath.Join(config.CachePath, "moddb", url.PathEscape(db.base.Host), url.PathEscape(modID)+".json")
+}
+
+func (db *HTTPModDB) readCache(modID string) (*cacheEntry, error) {
+	if config.CachePath == "" {
+		return nil, ErrNotCached
+	}
+
+	data, err := os.ReadFile(db.cachePath(modID))
+	if err != nil {
+		return nil, ErrNotCached
+	}
+
+	entry := &cacheEntry{}
+	err = json.Unmarshal(data, entry)
+	if err != nil {
+		return nil, ErrNotCached
+	}
+	return entry, nil
+}
+
+// writeCache stores entry, failures are ignored as cache is best effort
+func (db *HTTPModDB) writeCache(modID string, entry *cacheEntry) {
+	if config.CachePath == "" {
+		return
+	}
+
+	path := db.cachePath(modID)
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// Write to temp file first, so concurrent readers never see partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	tmp.Close()
+	if err != nil {
+		return
+	}
+	os.Rename(tmp.Name(), path)
+}
+
+func (e *cacheEntry) decode() (*Response, error) {
+	r := &Response{}
+	err := json.Unmarshal(e.Body, r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// fetch returns ModDB API response for the mod.
+// Cached response is used while it's younger than config.CacheTTL,
+// after that it's revalidated with a conditional request.
+func (db *HTTPModDB) fetch(modID string) (*Response, error) {
+	entry, cacheErr := db.readCache(modID)
+
+	if config.Offline {
+		if cacheErr != nil {
+			return nil, fmt.Errorf("%w: %s %w", ErrOffline, modID, cacheErr)
+		}
+		return entry.decode()
+	}
+
+	if config.Refresh {
+		entry, cacheErr = nil, ErrNotCached
+	}
+
+	if cacheErr == nil && time.Since(entry.Fetched) < config.CacheTTL {
+		return entry.decode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, db.url("api", "mod", modID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheErr == nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := db.do(db.client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cacheErr == nil:
+		entry.Fetched = time.Now()
+
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		entry = &cacheEntry{
+			Fetched:      time.Now(),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Body:         bytes.TrimSpace(body),
+		}
+
+	default:
+		return nil, fmt.Errorf("status: %s", resp.Status)
+	}
+
+	r, err := entry.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	db.writeCache(modID, entry)
+	return r, nil
+}
@@ -0,0 +1,189 @@
+package mod
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Changelog is release changelog converted to plain text
+type Changelog struct {
+	Version SemVer `json:"version"`
+	Created string `json:"created,omitempty"`
+	Text    string `json:"text"`
+}
+
+// Changelog returns changelogs of releases newer than installed version up to (including) to,
+// newest first. Zero to includes all newer releases.
+func (i *Info) Changelog(to SemVer) ([]Changelog, error) {
+	mod, err := i.FetchMod()
+	if err != nil {
+		return nil, fmt.Errorf("Info.Changelog: %w", err)
+	}
+
+	logs := []Changelog{}
+	for _, rel := range mod.Releases {
+		if rel.ModVersion.Compare(i.Version) <= 0 {
+			continue
+		}
+		if to.IsValid() && rel.ModVersion.Compare(to) > 0 {
+			continue
+		}
+		logs = append(logs, Changelog{
+			Version: rel.ModVersion,
+			Created: rel.Created,
+			Text:    HTMLToText(rel.Changelog),
+		})
+	}
+	return logs, nil
+}
+
+var (
+	tagName    = regexp.MustCompile(`^/?\s*([a-zA-Z0-9]+)`)
+	tagHref    = regexp.MustCompile(`(?i)\bhref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	spaces     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToText converts ModDB changelog HTML to readable terminal text
+func HTMLToText(s string) string {
+	b := &strings.Builder{}
+	var (
+		lists []int // Item counter of nested lists, -1 for unordered
+		href  string
+		skip  bool // Inside script or style
+		pre   bool
+	)
+
+	// newlines makes sure output ends with n line breaks, nothing is added at start
+	newlines := func(n int) {
+		out := b.String()
+		if strings.TrimSpace(out) == "" {
+			return
+		}
+		for have := len(out) - len(strings.TrimRight(out, "\n")); have < n; have++ {
+			b.WriteByte('\n')
+		}
+	}
+
+	for s != "" {
+		idx := strings.IndexByte(s, '<')
+		if idx < 0 {
+			idx = len(s)
+		}
+
+		if text := s[:idx]; !skip && text != "" {
+			text = html.UnescapeString(text)
+			if !pre {
+				text = spaces.ReplaceAllString(text, " ")
+				// Don't start lines with a space
+				if out := b.String(); out == "" || strings.HasSuffix(out, "\n") || strings.HasSuffix(out, " ") {
+					text = strings.TrimLeft(text, " ")
+				}
+			}
+			b.WriteString(text)
+		}
+		s = s[idx:]
+		if s == "" {
+			break
+		}
+
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			// Not a tag
+			b.WriteString(html.UnescapeString(s))
+			break
+		}
+		tag := s[1:end]
+		s = s[end+1:]
+
+		m := tagName.FindStringSubmatch(tag)
+		if m == nil {
+			continue
+		}
+		name := strings.ToLower(m[1])
+		closing := strings.HasPrefix(tag, "/")
+
+		switch name {
+		case "script", "style":
+			skip = !closing
+
+		case "pre":
+			pre = !closing
+			newlines(2)
+
+		case "br":
+			b.WriteByte('\n')
+
+		case "p", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "table":
+			newlines(2)
+
+		case "div", "tr":
+			newlines(1)
+
+		case "td", "th":
+			if !closing {
+				b.WriteByte(' ')
+			}
+
+		case "hr":
+			newlines(1)
+			b.WriteString("---")
+			newlines(1)
+
+		case "ul", "ol":
+			if closing {
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+				if len(lists) == 0 {
+					newlines(2)
+				}
+				continue
+			}
+
+			newlines(1)
+			if name == "ol" {
+				lists = append(lists, 0)
+			} else {
+				lists = append(lists, -1)
+			}
+
+		case "li":
+			if closing {
+				continue
+			}
+
+			newlines(1)
+			b.WriteString(strings.Repeat("  ", max(len(lists)-1, 0)))
+			if len(lists) > 0 && lists[len(lists)-1] >= 0 {
+				lists[len(lists)-1]++
+				fmt.Fprintf(b, "%d. ", lists[len(lists)-1])
+			} else {
+				b.WriteString("- ")
+			}
+
+		case "a":
+			if !closing {
+				href = ""
+				if m := tagHref.FindStringSubmatch(tag); m != nil {
+					href = html.UnescapeString(m[1] + m[2] + m[3])
+				}
+				continue
+			}
+
+			// Keep link targets visible, unless the text is the link itself
+			if href != "" && !strings.HasSuffix(b.String(), href) {
+				fmt.Fprintf(b, " (%s)", href)
+			}
+			href = ""
+		}
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,145 @@
+package mod
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"golang.org/x/mod/semver"
+)
+
+var ErrInvalidConstraint = errors.New("invalid version constraint")
+
+// Constraint is a set of version comparisons, all of them must match.
+// Supported forms: 1.4.2 (exact), 1.4 (any 1.4.x), =, <, <=, >, >=, ~1.4, ^1.4.
+// Comparisons are separated by spaces or commas: ">=1.2, <2.0.0".
+type Constraint []comparison
+
+type comparison struct {
+	op string
+	v  SemVer
+}
+
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{}
+	for field := range strings.FieldsFuncSeq(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+		op := field[:len(field)-len(strings.TrimLeft(field, "<>=~^"))]
+		v, err := NewSemVer(field[len(op):])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidConstraint, s)
+		}
+
+		// Index of the last part given: 0 in 1, 1 in 1.4, 2 in 1.4.2-rc.1+build.1
+		core, _, _ := strings.Cut(v.string, "+")
+		core, _, _ = strings.Cut(core, "-")
+		last := strings.Count(core, ".")
+
+		switch op {
+		case "":
+			if last == 2 {
+				c = append(c, comparison{"=", v})
+				continue
+			}
+			// Partial version matches every version with the prefix
+			c = append(c, comparison{">=", v}, comparison{"<", bump(v, last)})
+
+		case "~":
+			// ~1 allows 1.x.x, ~1.4 and ~1.4.2 allow 1.4.x
+			c = append(c, comparison{">=", v}, comparison{"<", bump(v, min(last, 1))})
+
+		case "^":
+			// Leftmost non-zero part can't change
+			idx := 0
+			for _, part := range strings.Split(strings.TrimPrefix(semver.Canonical(v.string), "v"), ".")[:2] {
+				if part != "0" {
+					break
+				}
+				idx++
+			}
+			c = append(c, comparison{">=", v}, comparison{"<", bump(v, idx)})
+
+		case "=", "<", "<=", ">", ">=":
+			c = append(c, comparison{op, v})
+
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidConstraint, s)
+		}
+	}
+
+	if len(c) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConstraint, s)
+	}
+	return c, nil
+}
+
+// bump returns the lowest version with part idx (0 major, 1 minor, 2 patch) incremented
+func bump(v SemVer, idx int) SemVer {
+	parts := strings.Split(strings.TrimPrefix(semver.Canonical(v.string), "v"), ".")
+	parts[2], _, _ = strings.Cut(parts[2], "-")
+
+	idx = min(idx, 2)
+	next := []string{"0", "0", "0"}
+	copy(next, parts[:idx])
+	n, _ := strconv.Atoi(parts[idx])
+	next[idx] = strconv.Itoa(n + 1)
+	return SemVer{"v" + strings.Join(next, ".")}
+}
+
+// Match reports whether v satisfies every comparison
+func (c Constraint) Match(v SemVer) bool {
+	for _, cmp := range c {
+		res := v.Compare(cmp.v)
+		ok := false
+		switch cmp.op {
+		case "=":
+			ok = res == 0
+		case "<":
+			// Pre-releases of the bound sort below it, but <2.0.0 means before 2.0.0 development
+			ok = res < 0 && !(v.PreRelease() && !cmp.v.PreRelease() && v.release().Compare(cmp.v) == 0)
+		case "<=":
+			ok = res <= 0
+		case ">":
+			ok = res > 0
+		case ">=":
+			ok = res >= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// release returns v without pre-release and build suffix
+func (v SemVer) release() SemVer {
+	c := semver.Canonical(v.string)
+	return SemVer{strings.TrimSuffix(c, semver.Prerelease(c))}
+}
+
+// constraints holds parsed version constraints of config.Mods
+var constraints = map[string]Constraint{}
+
+func init() {
+	// Invalid constraint is a config error, reported before anything is checked
+	for modID, p := range config.Mods {
+		if p.Version == "" {
+			continue
+		}
+
+		c, err := ParseConstraint(p.Version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config: mods.%s: %v\n", modID, err)
+			os.Exit(2)
+		}
+		constraints[modID] = c
+	}
+}
+
+// policy returns update policy of the mod from config.
+// Nil constraint allows every version.
+func (i *Info) policy() (Constraint, *bool) {
+	return constraints[i.ModID], config.Mods[i.ModID].PreRelease
+}
@@ -0,0 +1,91 @@
+package mod
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConstraintMatch(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		// Exact
+		{"1.4.2", "1.4.2", true},
+		{"1.4.2", "1.4.3", false},
+
+		// Partial version
+		{"1.4", "1.4.0", true},
+		{"1.4", "1.4.9", true},
+		{"1.4", "1.5.0", false},
+		{"1.4", "1.3.9", false},
+
+		// Tilde
+		{"~1", "1.0.0", true},
+		{"~1", "1.9.9", true},
+		{"~1", "2.0.0", false},
+		{"~1.4.2", "1.4.2", true},
+		{"~1.4.2", "1.4.9", true},
+		{"~1.4.2", "1.4.1", false},
+		{"~1.4.2", "1.5.0", false},
+
+		// Caret with leading zeros
+		{"^1.2.3", "1.9.0", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.3", true},
+		{"^0.0.3", "0.0.4", false},
+
+		// Comparisons
+		{">=1.2, <2.0.0", "1.2.0", true},
+		{">=1.2 <2.0.0", "2.0.0", false},
+		{">1.2.0", "1.2.0", false},
+		{"<=1.2.0", "1.2.0", true},
+
+		// Pre-releases
+		{"<2.0.0", "2.0.0-rc.1", false},
+		{"<2.0.0", "1.9.0-rc.1", true},
+		{"<2.0.0-rc.3", "2.0.0-rc.1", true},
+		{"~1.4", "1.5.0-rc.1", false},
+		{"^1.2.3", "1.3.0-rc.1", true},
+		{">=1.4.2", "1.4.2-rc.1", false},
+
+		// Pre-release and build metadata with dots
+		{"1.4.2+build.1", "1.4.2", true},
+		{"1.4.2+build.1", "1.4.3", false},
+		{"1.4.2-rc.1+b", "1.4.2-rc.1", true},
+		{"1.4.2-rc.1+b", "1.4.2", false},
+		{"~1.4.2+build.1", "1.4.9", true},
+		{"~1.4.2+build.1", "1.5.0", false},
+		{"^1.4.2-rc.1", "1.9.0", true},
+		{"^1.4.2-rc.1", "2.0.0", false},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q): %v", tt.constraint, err)
+			continue
+		}
+
+		v, err := NewSemVer(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := c.Match(v); got != tt.want {
+			t.Errorf("%q.Match(%s) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, s := range []string{"", ",", "abc", "=>1.0", "!1.0", "1.x", "1.4+build.1", "1.4.2+", "1.4.2+b..1", "~1-rc.1"} {
+		_, err := ParseConstraint(s)
+		if !errors.Is(err, ErrInvalidConstraint) {
+			t.Errorf("ParseConstraint(%q) = %v, want %v", s, err, ErrInvalidConstraint)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package mod
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Duplicate is a mod installed more than once
+type Duplicate struct {
+	ModID  string
+	Keep   *Info   // Newest version, zip and recently modified copies are preferred
+	Extra  []*Info // Other copies
+	Reason string
+}
+
+// Duplicates groups mods by modid and returns groups with multiple copies.
+// Invalid mods are not included.
+func Duplicates(mods []*Info) []Duplicate {
+	groups := map[string][]*Info{}
+	order := []string{}
+	for _, m := range mods {
+		if m.Error != nil || m.ModID == "" {
+			continue
+		}
+
+		key := strings.ToLower(m.ModID)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+
+		// Same file loaded twice isn't a copy
+		if slices.ContainsFunc(groups[key], m.sameFile) {
+			continue
+		}
+		groups[key] = append(groups[key], m)
+	}
+
+	dups := []Duplicate{}
+	for _, key := range order {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+
+		slices.SortStableFunc(group, func(a, b *Info) int {
+			if c := b.Version.Compare(a.Version); c != 0 {
+				return c
+			}
+			// Folder copies are usually unpacked releases
+			if c := compareBool(a.isDir(), b.isDir()); c != 0 {
+				return c
+			}
+			return b.modTime().Compare(a.modTime())
+		})
+
+		reasons := []string{}
+		if group[0].Version.Compare(group[len(group)-1].Version) != 0 {
+			reasons = append(reasons, "multiple versions")
+		}
+		dirs := len(slices.DeleteFunc(slices.Clone(group), func(m *Info) bool { return !m.isDir() }))
+		if dirs > 0 && dirs < len(group) {
+			reasons = append(reasons, "folder and zip copies")
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "multiple copies")
+		}
+
+		d := Duplicate{ModID: group[0].ModID, Keep: group[0], Extra: group[1:], Reason: strings.Join(reasons, ", ")}
+		dups = append(dups, d)
+	}
+	return dups
+}
+
+// sameFile reports whether both mods were loaded from the same file or folder
+func (i *Info) sameFile(other *Info) bool {
+	if i.Path == other.Path {
+		return true
+	}
+
+	a, err := os.Stat(i.Path)
+	if err != nil {
+		return false
+	}
+	b, err := os.Stat(other.Path)
+	return err == nil && os.SameFile(a, b)
+}
+
+func (i *Info) isDir() bool {
+	stat, err := os.Stat(i.Path)
+	return err == nil && stat.IsDir()
+}
+
+func (i *Info) modTime() time.Time {
+	stat, err := os.Stat(i.Path)
+	if err != nil {
+		return time.Time{}
+	}
+	return stat.ModTime()
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
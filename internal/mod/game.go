@@ -0,0 +1,35 @@
+package mod
+
+import (
+	"sync"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/game"
+)
+
+// InstalledGame returns the version of the detected game installation
+var InstalledGame = sync.OnceValues(func() (SemVer, error) {
+	dir, err := game.Find(config.GamePath)
+	if err != nil {
+		return SemVer{}, err
+	}
+
+	v, err := game.Version(dir)
+	if err != nil {
+		return SemVer{}, err
+	}
+	return NewSemVer(v)
+})
+
+// GameVersion returns the game version used to select compatible releases,
+// falling back to the installed game version.
+// Zero value means releases for any game version are accepted.
+func GameVersion() SemVer {
+	if config.GameVersion != "" {
+		v, _ := NewSemVer(config.GameVersion)
+		return v
+	}
+
+	v, _ := InstalledGame()
+	return v
+}
@@ -7,11 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
-	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"github.com/rafalb8/VSModUpdater/v2/internal/config"
@@ -22,9 +19,12 @@ import (
 //   - [Wiki](https://wiki.vintagestory.at/Modding:Modinfo)
 //   - [Docs](https://apidocs.vintagestory.at/api/Vintagestory.API.Common.Info.html)
 type Info struct {
-	Path    string `json:"-"`
-	Error   error  `json:"-"`
-	AssetID int    `json:"-"`
+	Path     string `json:"-"`
+	Dir      string `json:"-"` // Mod directory the mod was found in
+	Error    error  `json:"-"`
+	AssetID  int    `json:"-"`
+	Disabled bool   `json:"-"` // Disabled in the game mod manager
+	page     string
 
 	Type             Type              `json:"type"`
 	Name             string            `json:"name"`
@@ -44,9 +44,9 @@ type Info struct {
 }
 
 // Returns Info slice from zip files
-func InfoFromPath(path string) ([]*Info, error) {
+func InfoFromPath(dir string) ([]*Info, error) {
 	mods := []*Info{}
-	err := filepath.WalkDir(config.ModPath, func(path string, d fs.DirEntry, err error) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -55,7 +55,7 @@ func InfoFromPath(path string) ([]*Info, error) {
 
 		switch {
 		case d.IsDir():
-			if path == config.ModPath {
+			if path == dir {
 				return nil
 			}
 			modFS = os.DirFS(path)
@@ -64,7 +64,7 @@ func InfoFromPath(path string) ([]*Info, error) {
 		case filepath.Ext(path) == ".zip":
 			r, err := zip.OpenReader(path)
 			if err != nil {
-				mods = append(mods, &Info{Path: path, Error: err})
+				mods = append(mods, &Info{Path: path, Dir: dir, Error: err})
 				return nil
 			}
 			defer r.Close()
@@ -74,14 +74,26 @@ func InfoFromPath(path string) ([]*Info, error) {
 			return nil
 		}
 
-		mods = append(mods, parseModFS(modFS, path))
+		info := parseModFS(modFS, path)
+		info.Dir = dir
+		mods = append(mods, info)
 		return err
 	})
-	return mods, err
+	if err != nil {
+		return mods, err
+	}
+
+	// Unreadable settings shouldn't prevent updates, mods are treated as enabled
+	disabled, _ := DisabledMods()
+	for _, m := range mods {
+		m.Disabled = m.ModID != "" && m.isDisabledBy(disabled)
+	}
+	return mods, nil
 }
 
 func parseModFS(modFS fs.FS, path string) *Info {
-	info := &Info{Path: path}
+	// Defaults of the game for missing fields
+	info := &Info{Path: path, Side: Universal, RequiredOnClient: true, RequiredOnServer: true}
 
 	data, err := fs.ReadFile(modFS, "modinfo.json")
 	if err != nil {
@@ -109,12 +121,12 @@ func parseModFS(modFS fs.FS, path string) *Info {
 
 // Page returns mod page url
 func (i *Info) Page() string {
-	uri, _ := url.JoinPath("https://mods.vintagestory.at/", i.ModID)
-
-	r, _ := http.Head(uri)
-	if r.StatusCode != http.StatusOK {
-		uri, _ = url.JoinPath("https://mods.vintagestory.at/show/mod/", strconv.Itoa(i.AssetID))
+	if i.page != "" {
+		return i.page
 	}
+
+	uri := DB.Page(i.ModID, i.AssetID)
+	i.page = uri
 	return uri
 }
 
@@ -151,9 +163,16 @@ func (i *Info) Details() string {
 	sb.WriteString("\nVersion:\t")
 	sb.WriteString(i.Version.String())
 
+	if i.Disabled {
+		sb.WriteString("\nState:\t\tdisabled")
+	}
+
 	if gameVer, ok := i.Dependencies["game"]; ok {
 		if gameVer == "*" || gameVer == "" {
 			gameVer = "any"
+		} else if !i.IsGameSupported() {
+			installed, _ := InstalledGame()
+			gameVer += " (installed " + installed.String() + " is too old)"
 		}
 		sb.WriteString("\nGame Version:\t")
 		sb.WriteString(gameVer)
@@ -171,8 +190,30 @@ func (i *Info) Details() string {
 	return sb.String()
 }
 
-// CheckUpdates returns the url to the latest compatible mod version.
+// IsGameSupported reports whether the installed game satisfies
+// the minimum game version from mod dependencies.
+// Returns true if either version is unknown.
+func (i *Info) IsGameSupported() bool {
+	installed, err := InstalledGame()
+	if err != nil {
+		return true
+	}
+
+	required, err := NewSemVer(i.Dependencies["game"])
+	if err != nil {
+		return true
+	}
+	return installed.Compare(required) >= 0
+}
+
+// CheckUpdates returns the url to the latest mod version compatible with the target game version.
 func (i *Info) CheckUpdates() (Update, error) {
+	return i.CheckUpdatesFor(GameVersion())
+}
+
+// CheckUpdatesFor returns the url to the latest mod version supporting game.
+// Zero game version accepts releases for any game version.
+func (i *Info) CheckUpdatesFor(game SemVer) (Update, error) {
 	if i.ModID == "" {
 		return Update{}, ErrNoModID
 	}
@@ -182,36 +223,29 @@ func (i *Info) CheckUpdates() (Update, error) {
 		return Update{}, fmt.Errorf("Info.CheckUpdates: %w", err)
 	}
 
+	constraint, preRelease := i.policy()
+
 	allowDev := cmp.Or(i.Version.PreRelease(), config.PreRelease)
-	return i.findLatestUpdate(mod, allowDev)
+	if preRelease != nil {
+		allowDev = *preRelease
+	}
+	return i.findLatestUpdate(mod, allowDev, game, constraint)
 }
 
 func (i *Info) FetchMod() (*Mod, error) {
-	uri, err := url.JoinPath("https://mods.vintagestory.at/api/mod/", i.ModID)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := http.Get(uri)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	r := &Response{}
-	err = json.NewDecoder(resp.Body).Decode(r)
+	mod, err := DB.FetchMod(i.ModID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Cache AssetID for i.Page()
-	i.AssetID = r.Mod.AssetID
-	return &r.Mod, nil
+	i.AssetID = mod.AssetID
+	return mod, nil
 }
 
-func (i *Info) findLatestUpdate(mod *Mod, allowDev bool) (Update, error) {
+func (i *Info) findLatestUpdate(mod *Mod, allowDev bool, game SemVer, constraint Constraint) (Update, error) {
 	err := ErrNoUpdate
-	upd := Update{Name: mod.Name}
+	upd := Update{ModID: i.ModID, Name: mod.Name, Dir: i.Dir}
 
 	for _, rel := range mod.Releases {
 		if !allowDev {
@@ -223,7 +257,8 @@ func (i *Info) findLatestUpdate(mod *Mod, allowDev bool) (Update, error) {
 				continue
 			}
 
-			if IsAllPreRelease(rel.Tags) {
+			// Pre-release game target explicitly accepts pre-release game tags
+			if IsAllPreRelease(rel.Tags) && !game.PreRelease() {
 				if err == ErrNoUpdate {
 					err = ErrUnstableSkip
 					upd.Version = rel.ModVersion
@@ -232,11 +267,30 @@ func (i *Info) findLatestUpdate(mod *Mod, allowDev bool) (Update, error) {
 			}
 		}
 
+		// Held back stable release is more relevant than skipped pre-release
+		if constraint != nil && !constraint.Match(rel.ModVersion) {
+			if (err == ErrNoUpdate || err == ErrPreReleaseSkip || err == ErrUnstableSkip) && rel.ModVersion.Compare(i.Version) > 0 {
+				err = ErrConstraintSkip
+				upd.Version = rel.ModVersion
+			}
+			continue
+		}
+
+		if !IsGameCompatible(rel.Tags, game) {
+			if err == ErrNoUpdate && rel.ModVersion.Compare(i.Version) > 0 {
+				err = ErrGameVersionSkip
+				upd.Version = rel.ModVersion
+				upd.GameVersion = GetLatestVersion(rel.Tags)
+			}
+			continue
+		}
+
 		// if ModVersion > local, we found update
 		if rel.ModVersion.Compare(i.Version) > 0 {
 			upd.URL = rel.Mainfile
 			upd.Version = rel.ModVersion
 			upd.Filename = rel.Filename
+			upd.GameVersion = GetLatestVersion(rel.Tags)
 			return upd, nil
 		}
 
@@ -246,24 +300,32 @@ func (i *Info) findLatestUpdate(mod *Mod, allowDev bool) (Update, error) {
 	return upd, err
 }
 
-func (i *Info) Backup() error {
-	err := os.MkdirAll(config.BackupPath, 0o755)
+// IsClientOnly reports whether the mod isn't used by dedicated servers
+func (i *Info) IsClientOnly() bool {
+	return i.Side == Client || !bool(i.RequiredOnServer)
+}
+
+// Backup moves mod into dir
+func (i *Info) Backup(dir string) error {
+	err := os.MkdirAll(dir, 0o755)
 	if err != nil {
 		return err
 	}
 
 	oldPath := i.Path
-	i.Path = filepath.Join(config.BackupPath, filepath.Base(i.Path))
+	i.Path = filepath.Join(dir, filepath.Base(i.Path))
 	return os.Rename(oldPath, i.Path)
 }
 
+// Restore moves backed up mod back to its mod directory
 func (i *Info) Restore() error {
-	err := os.MkdirAll(config.ModPath, 0o755)
+	dir := cmp.Or(i.Dir, config.ModPath)
+	err := os.MkdirAll(dir, 0o755)
 	if err != nil {
 		return err
 	}
 
 	oldPath := i.Path
-	i.Path = filepath.Join(config.ModPath, filepath.Base(i.Path))
+	i.Path = filepath.Join(dir, filepath.Base(i.Path))
 	return os.Rename(oldPath, i.Path)
 }
@@ -0,0 +1,148 @@
+package mod
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/tailscale/hujson"
+)
+
+const LockfileVersion = 1
+
+// Lockfile describes an exact mod set, allowing byte-for-byte reproduction of the mod folder
+type Lockfile struct {
+	Version int         `json:"version"`
+	Mods    []LockEntry `json:"mods"`
+}
+
+type LockEntry struct {
+	ModID     string  `json:"modid"`
+	Name      string  `json:"name,omitempty"`
+	Version   SemVer  `json:"version"`
+	ReleaseID int     `json:"releaseid,omitempty"`
+	FileID    int     `json:"fileid,omitempty"`
+	Filename  string  `json:"filename"`
+	URL       string  `json:"url"`
+	Side      AppSide `json:"side"`
+	SHA256    string  `json:"sha256,omitempty"` // Empty for mods installed as folders
+	Disabled  bool    `json:"disabled,omitempty"`
+}
+
+// IsLockfile reports whether data looks like a lockfile rather than a plain mod list
+func IsLockfile(data []byte) bool {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+func ReadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLockfile(data)
+}
+
+func ParseLockfile(data []byte) (*Lockfile, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	data, err := hujson.Standardize(data)
+	if err != nil {
+		return nil, fmt.Errorf("ParseLockfile: %w", err)
+	}
+
+	lock := &Lockfile{}
+	err = json.Unmarshal(data, lock)
+	if err != nil {
+		return nil, fmt.Errorf("ParseLockfile: %w", err)
+	}
+
+	if lock.Version > LockfileVersion {
+		return nil, fmt.Errorf("ParseLockfile: unsupported lockfile version %d", lock.Version)
+	}
+	return lock, nil
+}
+
+func (l *Lockfile) Write(path string) error {
+	data, err := json.MarshalIndent(l, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Lock returns lockfile entry of the installed mod version
+func (i *Info) Lock() (LockEntry, error) {
+	if i.ModID == "" {
+		return LockEntry{}, ErrNoModID
+	}
+
+	mod, err := i.FetchMod()
+	if err != nil {
+		return LockEntry{}, fmt.Errorf("Info.Lock: %w", err)
+	}
+
+	for _, rel := range mod.Releases {
+		if rel.ModVersion.Compare(i.Version) != 0 {
+			continue
+		}
+
+		entry := LockEntry{
+			ModID:     i.ModID,
+			Name:      i.Name,
+			Version:   rel.ModVersion,
+			ReleaseID: rel.ReleaseID,
+			FileID:    rel.FileID,
+			Filename:  rel.Filename,
+			URL:       rel.Mainfile,
+			Side:      i.Side,
+			Disabled:  i.Disabled,
+		}
+
+		// Folder mods can't be compared with release archive
+		if filepath.Ext(i.Path) == ".zip" {
+			entry.SHA256, err = HashFile(i.Path)
+			if err != nil {
+				return LockEntry{}, fmt.Errorf("Info.Lock: %w", err)
+			}
+		}
+		return entry, nil
+	}
+	return LockEntry{}, fmt.Errorf("Info.Lock: %w for %s@%s", ErrNoRelease, i.ModID, i.Version)
+}
+
+// Update returns update downloading the locked release
+func (e LockEntry) Update() Update {
+	return Update{
+		ModID:    e.ModID,
+		Name:     cmp.Or(e.Name, e.ModID),
+		URL:      e.URL,
+		Version:  e.Version,
+		Filename: e.Filename,
+		SHA256:   e.SHA256,
+		Disabled: e.Disabled,
+	}
+}
+
+// HashFile returns hex encoded SHA-256 of the file
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,160 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+// ModDB provides access to the mod database.
+// Every network request made by the updater goes through it.
+type ModDB interface {
+	// FetchMod returns mod details with all releases
+	FetchMod(modID string) (*Mod, error)
+
+	// Download opens release file, size is -1 if unknown
+	Download(uri string) (body io.ReadCloser, size int64, err error)
+
+	// Page returns mod page url
+	Page(modID string, assetID int) string
+
+	// Search returns mods matching text, sorted by orderBy (downloads, trendingpoints, lastreleased)
+	Search(text, orderBy string) ([]ListedMod, error)
+}
+
+// DB is the ModDB used by all mod operations
+var DB ModDB
+
+func init() {
+	db, err := NewHTTPModDB(config.APIURL)
+	if err != nil {
+		panic(err)
+	}
+	DB = db
+}
+
+// HTTPModDB is ModDB client for mods.vintagestory.at compatible servers
+type HTTPModDB struct {
+	base     *url.URL
+	client   *http.Client // API requests, limited by config.Timeout
+	download *http.Client // Release files, limited by config.DownloadTimeout
+	retries  atomic.Int64
+}
+
+func NewHTTPModDB(baseURL string) (*HTTPModDB, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("NewHTTPModDB: %w", err)
+	}
+
+	// Connections are shared between workers
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = max(config.Jobs, 2)
+	transport.MaxConnsPerHost = max(config.Jobs, 2) * 2
+
+	return &HTTPModDB{
+		base:     base,
+		client:   &http.Client{Transport: transport, Timeout: config.Timeout},
+		download: &http.Client{Transport: transport, Timeout: config.DownloadTimeout},
+	}, nil
+}
+
+// url returns absolute url of path relative to the base url
+func (db *HTTPModDB) url(path ...string) string {
+	return db.base.JoinPath(path...).String()
+}
+
+func (db *HTTPModDB) FetchMod(modID string) (*Mod, error) {
+	r, err := db.fetch(modID)
+	if err != nil {
+		return nil, err
+	}
+	return &r.Mod, nil
+}
+
+func (db *HTTPModDB) Download(uri string) (io.ReadCloser, int64, error) {
+	if config.Offline {
+		return nil, 0, ErrOffline
+	}
+
+	ref, err := url.Parse(uri)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Make sure queries are escaped
+	ref.RawQuery = url.QueryEscape(ref.RawQuery)
+
+	// Relative file urls are served by the mirror itself
+	resp, err := db.get(db.download, db.base.ResolveReference(ref).String())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("status: %s", resp.Status)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+func (db *HTTPModDB) Page(modID string, assetID int) string {
+	uri := db.url(modID)
+	if config.Offline {
+		return uri
+	}
+
+	req, err := http.NewRequest(http.MethodHead, uri, nil)
+	if err != nil {
+		return uri
+	}
+
+	r, err := db.do(db.client, req)
+	if err == nil {
+		r.Body.Close()
+	}
+	if err != nil || r.StatusCode != http.StatusOK {
+		uri = db.url("show", "mod", strconv.Itoa(assetID))
+	}
+	return uri
+}
+
+func (db *HTTPModDB) Search(text, orderBy string) ([]ListedMod, error) {
+	if config.Offline {
+		return nil, ErrOffline
+	}
+
+	u := db.base.JoinPath("api", "mods")
+	query := url.Values{}
+	if text != "" {
+		query.Set("text", text)
+	}
+	if orderBy != "" {
+		query.Set("orderby", orderBy)
+		query.Set("orderdirection", "desc")
+	}
+	u.RawQuery = query.Encode()
+
+	resp, err := db.get(db.client, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status: %s", resp.Status)
+	}
+
+	list := &ListResponse{}
+	err = json.NewDecoder(resp.Body).Decode(list)
+	if err != nil {
+		return nil, err
+	}
+	return list.Mods, nil
+}
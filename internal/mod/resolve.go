@@ -0,0 +1,176 @@
+package mod
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+var (
+	ErrUnsatisfiable = errors.New("unsatisfiable dependency")
+	ErrCyclic        = errors.New("cyclic dependency")
+)
+
+// Mods shipped with the game, never fetched from ModDB
+var builtinMods = map[string]struct{}{
+	"game":     {},
+	"survival": {},
+	"creative": {},
+}
+
+type visitState uint8
+
+const (
+	unvisited visitState = iota
+	visiting
+	visited
+)
+
+type resolver struct {
+	installed map[string]*Info
+	planned   map[string]Update
+	order     []string // ModIDs of dependencies in the order they were added
+	state     map[string]visitState
+	stack     []string
+	errs      []error
+}
+
+// Resolve walks dependency graph of the selected updates and returns
+// additional updates required to satisfy it (missing or too old dependencies).
+// Unsatisfiable and cyclic dependencies are reported as joined error.
+func Resolve(installed []*Info, selected []Update) ([]Update, error) {
+	r := &resolver{
+		installed: make(map[string]*Info, len(installed)),
+		planned:   make(map[string]Update, len(selected)),
+		state:     map[string]visitState{},
+	}
+
+	for _, info := range installed {
+		if info.Error == nil && info.ModID != "" {
+			r.installed[info.ModID] = info
+		}
+	}
+
+	for _, upd := range selected {
+		r.planned[upd.ModID] = upd
+	}
+
+	// Release archives are fetched concurrently, visit reads them from temporary files
+	prefetch(selected)
+
+	for _, upd := range selected {
+		r.visit(upd.ModID)
+	}
+
+	deps := make([]Update, 0, len(r.order))
+	for _, modID := range r.order {
+		deps = append(deps, r.planned[modID])
+	}
+	return deps, errors.Join(r.errs...)
+}
+
+// prefetch fetches release archives of the updates using config.Jobs workers.
+// Errors are ignored here, visit fetches the release again and reports them.
+func prefetch(updates []Update) {
+	sem := make(chan struct{}, max(config.Jobs, 1))
+	var wg sync.WaitGroup
+	for _, upd := range updates {
+		if filepath.Ext(upd.Filename) != ".zip" {
+			continue
+		}
+
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			upd.fetch()
+		})
+	}
+	wg.Wait()
+}
+
+func (r *resolver) visit(modID string) {
+	switch r.state[modID] {
+	case visited:
+		return
+	case visiting:
+		cycle := slices.Concat(r.stack[slices.Index(r.stack, modID):], []string{modID})
+		r.errs = append(r.errs, fmt.Errorf("%w: %s", ErrCyclic, strings.Join(cycle, " -> ")))
+		return
+	}
+
+	r.state[modID] = visiting
+	r.stack = append(r.stack, modID)
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+		r.state[modID] = visited
+	}()
+
+	upd := r.planned[modID]
+	deps, err := upd.Dependencies()
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s@%s: %w", modID, upd.Version, err))
+		return
+	}
+
+	for _, depID := range slices.Sorted(maps.Keys(deps)) {
+		if _, ok := builtinMods[depID]; ok {
+			continue
+		}
+
+		// Empty or wildcard version accepts any version
+		minVersion, _ := NewSemVer(deps[depID])
+
+		if !r.require(modID, depID, minVersion) {
+			continue
+		}
+
+		if _, ok := r.planned[depID]; ok {
+			r.visit(depID)
+		}
+	}
+}
+
+// require makes sure depID is installed or planned in at least minVersion.
+// Returns false if dependency can't be satisfied.
+func (r *resolver) require(modID, depID string, minVersion SemVer) bool {
+	if upd, ok := r.planned[depID]; ok {
+		if upd.Version.Compare(minVersion) >= 0 {
+			return true
+		}
+		r.errs = append(r.errs, fmt.Errorf("%w: %s requires %s@%s, but %s is selected",
+			ErrUnsatisfiable, modID, depID, minVersion, upd.Version))
+		return false
+	}
+
+	info, ok := r.installed[depID]
+	if ok && info.Version.Compare(minVersion) >= 0 {
+		return true
+	}
+	if !ok {
+		info = &Info{ModID: depID}
+	}
+
+	upd, err := info.CheckUpdates()
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%w: %s requires %s@%s: %w",
+			ErrUnsatisfiable, modID, depID, minVersion, err))
+		return false
+	}
+
+	if upd.Version.Compare(minVersion) < 0 {
+		r.errs = append(r.errs, fmt.Errorf("%w: %s requires %s@%s, latest compatible is %s",
+			ErrUnsatisfiable, modID, depID, minVersion, upd.Version))
+		return false
+	}
+
+	upd.RequiredBy = modID
+	r.planned[depID] = upd
+	r.order = append(r.order, depID)
+	return true
+}
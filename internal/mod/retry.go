@@ -0,0 +1,105 @@
+package mod
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+const (
+	retryBaseDelay  = 500 * time.Millisecond
+	retryMaxDelay   = 30 * time.Second
+	retryAfterLimit = 2 * time.Minute // Longer Retry-After would stall the whole run
+)
+
+// get sends GET request, see do
+func (db *HTTPModDB) get(client *http.Client, uri string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return db.do(client, req)
+}
+
+// do sends idempotent request without body. Network errors, 429 and 5xx responses
+// are retried up to config.Retries times with exponential backoff and jitter,
+// Retry-After of the response is honoured.
+func (db *HTTPModDB) do(client *http.Client, req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= config.Retries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = min(after, retryAfterLimit)
+			}
+
+			// Drain body, so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		// Count requests, not attempts
+		if attempt == 0 {
+			db.retries.Add(1)
+		}
+		time.Sleep(delay)
+	}
+}
+
+// Retries returns number of requests that had to be retried
+func (db *HTTPModDB) Retries() int64 {
+	return db.retries.Load()
+}
+
+// Retries returns number of requests retried by DB, zero if DB doesn't retry
+func Retries() int64 {
+	if db, ok := DB.(interface{ Retries() int64 }); ok {
+		return db.Retries()
+	}
+	return 0
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns delay before retry attempt: exponential with equal jitter
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for range attempt {
+		// Stop doubling at the limit, large attempts would overflow
+		if delay >= retryMaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	delay = min(delay, retryMaxDelay)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryAfter parses Retry-After header: delay in seconds or http date
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,62 @@
+package mod
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/game"
+	"github.com/tailscale/hujson"
+)
+
+// serverConfig contains the parts of the dedicated server serverconfig.json used by the updater
+type serverConfig struct {
+	ModPaths []string `json:"ModPaths"`
+}
+
+// ServerModPaths returns mod directories configured in serverconfig.json of config.DataPath.
+// Relative paths are resolved against the game installation like the server does,
+// they are returned as unresolved if the installation can't be found.
+func ServerModPaths() (dirs, unresolved []string, err error) {
+	path := filepath.Join(config.DataPath, "serverconfig.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ServerModPaths: %w", err)
+	}
+
+	data, err = hujson.Standardize(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if err != nil {
+		return nil, nil, fmt.Errorf("ServerModPaths: %s: %w", path, err)
+	}
+
+	cfg := &serverConfig{}
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ServerModPaths: %s: %w", path, err)
+	}
+
+	if len(cfg.ModPaths) == 0 {
+		return nil, nil, fmt.Errorf("ServerModPaths: %s: no ModPaths", path)
+	}
+
+	gameDir, gameErr := game.Find(config.GamePath)
+	for _, dir := range cfg.ModPaths {
+		if !filepath.IsAbs(dir) {
+			if gameErr != nil {
+				unresolved = append(unresolved, dir)
+				continue
+			}
+			dir = filepath.Join(gameDir, dir)
+		}
+
+		dir = filepath.Clean(dir)
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, unresolved, nil
+}
@@ -0,0 +1,118 @@
+package mod
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/tailscale/hujson"
+)
+
+// clientSettings contains the parts of the game clientsettings.json used by the updater
+type clientSettings struct {
+	StringListSettings struct {
+		DisabledMods []string `json:"disabledMods"`
+	} `json:"stringListSettings"`
+}
+
+func clientSettingsPath() string {
+	return filepath.Join(config.DataPath, "clientsettings.json")
+}
+
+func readClientSettings() (hujson.Value, *clientSettings, error) {
+	data, err := os.ReadFile(clientSettingsPath())
+	if err != nil {
+		return hujson.Value{}, nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	value, err := hujson.Parse(data)
+	if err != nil {
+		return hujson.Value{}, nil, fmt.Errorf("clientsettings.json: %w", err)
+	}
+
+	std := value.Clone()
+	std.Standardize()
+
+	settings := &clientSettings{}
+	err = json.Unmarshal(std.Pack(), settings)
+	if err != nil {
+		return hujson.Value{}, nil, fmt.Errorf("clientsettings.json: %w", err)
+	}
+	return value, settings, nil
+}
+
+// DisabledMods returns entries of the in-game disabled mod list (modid@version).
+// Missing settings file means no mod is disabled.
+func DisabledMods() ([]string, error) {
+	_, settings, err := readClientSettings()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings.StringListSettings.DisabledMods, nil
+}
+
+// DisableMods adds mods to the in-game disabled mod list.
+// Formatting of the settings file is preserved.
+func DisableMods(mods ...*Info) error {
+	value, settings, err := readClientSettings()
+	if err != nil {
+		return fmt.Errorf("DisableMods: %w", err)
+	}
+
+	disabled := settings.StringListSettings.DisabledMods
+	for _, m := range mods {
+		if !m.isDisabledBy(disabled) {
+			disabled = append(disabled, m.disabledEntry())
+		}
+	}
+
+	list, err := json.Marshal(disabled)
+	if err != nil {
+		return fmt.Errorf("DisableMods: %w", err)
+	}
+
+	// JSON Patch "add" replaces existing member
+	path, patchValue := "/stringListSettings/disabledMods", list
+	if value.Find("/stringListSettings") == nil {
+		path, patchValue = "/stringListSettings", fmt.Appendf(nil, `{"disabledMods":%s}`, list)
+	}
+
+	patch := fmt.Appendf(nil, `[{"op":"add","path":%q,"value":%s}]`, path, patchValue)
+	err = value.Patch(patch)
+	if err != nil {
+		return fmt.Errorf("DisableMods: %w", err)
+	}
+
+	return os.WriteFile(clientSettingsPath(), value.Pack(), 0o644)
+}
+
+// disabledEntry returns the entry used by the game to disable the mod
+func (i *Info) disabledEntry() string {
+	return i.ModID + "@" + strings.TrimPrefix(i.Version.String(), "v")
+}
+
+// isDisabledBy reports whether the mod is on the disabled list.
+// Entries without version disable every version of the mod.
+func (i *Info) isDisabledBy(disabled []string) bool {
+	return slices.ContainsFunc(disabled, func(entry string) bool {
+		modID, version, found := strings.Cut(entry, "@")
+		if !strings.EqualFold(modID, i.ModID) {
+			return false
+		}
+		if !found {
+			return true
+		}
+
+		v, err := NewSemVer(version)
+		return err == nil && v.Compare(i.Version) == 0
+	})
+}
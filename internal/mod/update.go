@@ -1,26 +1,39 @@
 package mod
 
 import (
-	"encoding/json"
+	"archive/zip"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/rafalb8/VSModUpdater/v2/internal/config"
 )
 
 type Update struct {
-	Name     string
-	URL      string
-	Version  SemVer
-	Filename string
+	ModID       string
+	Name        string
+	URL         string
+	Version     SemVer
+	Filename    string
+	GameVersion SemVer // Latest game version supported by the release
+	RequiredBy  string // ModID of the mod depending on this update, empty if selected directly
+	SHA256      string // Expected hash of the downloaded file, not checked if empty
+	Disabled    bool   // Mod should be disabled in the game after installation
+	Dir         string // Mod directory to download into, config.ModPath if empty
 }
 
+// UpdateFromString parses mod list line: modid@version [disabled]
 func UpdateFromString(line string) (upd Update, err error) {
+	line, state, _ := strings.Cut(strings.TrimSpace(line), " ")
+	upd.Disabled = strings.TrimSpace(state) == "disabled"
+
 	modid, version, found := strings.Cut(line, "@")
 	if !found {
 		return upd, fmt.Errorf("failed to parse info")
@@ -31,60 +44,264 @@ func UpdateFromString(line string) (upd Update, err error) {
 		return upd, err
 	}
 
-	uri, err := url.JoinPath("https://mods.vintagestory.at/api/mod/", modid)
-	if err != nil {
-		return upd, fmt.Errorf("UpdateFromString: %w", err)
-	}
-
-	resp, err := http.Get(uri)
-	if err != nil {
-		return upd, fmt.Errorf("UpdateFromString: %w", err)
-	}
-	defer resp.Body.Close()
-
-	r := &Response{}
-	err = json.NewDecoder(resp.Body).Decode(r)
+	mod, err := DB.FetchMod(modid)
 	if err != nil {
 		return upd, fmt.Errorf("UpdateFromString: %w", err)
 	}
 
-	upd.Name = r.Mod.Name
-	for _, release := range r.Mod.Releases {
+	upd.ModID = modid
+	upd.Name = mod.Name
+	for _, release := range mod.Releases {
 		if release.ModVersion.Compare(semver) == 0 {
 			upd.URL = release.Mainfile
 			upd.Version = release.ModVersion
 			upd.Filename = release.Filename
+			upd.GameVersion = GetLatestVersion(release.Tags)
 			return
 		}
 	}
 	return upd, fmt.Errorf("UpdateFromString: no release found for %s", modid)
 }
 
+// UpdateFromTarget resolves install target: modid, modid@version, modid@latest or mod page url.
+// Without version the newest release compatible with the target game version is used.
+func UpdateFromTarget(target string) (Update, error) {
+	target = strings.TrimSpace(target)
+	if strings.Contains(target, "://") {
+		modID, err := modIDFromURL(target)
+		if err != nil {
+			return Update{}, fmt.Errorf("UpdateFromTarget: %w", err)
+		}
+		target = modID
+	}
+
+	modID, version, _ := strings.Cut(target, "@")
+	if modID == "" {
+		return Update{}, fmt.Errorf("UpdateFromTarget: %w", ErrNoModID)
+	}
+
+	if version != "" && version != "latest" {
+		return UpdateFromString(modID + "@" + version)
+	}
+
+	info := &Info{ModID: modID}
+	upd, err := info.CheckUpdates()
+	if err == ErrNoUpdate {
+		err = ErrNoRelease
+	}
+	if err != nil {
+		return upd, fmt.Errorf("UpdateFromTarget: %w", err)
+	}
+	return upd, nil
+}
+
+// modIDFromURL returns modid from mod page url: /<modid> or /show/mod/<assetid>
+func modIDFromURL(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	path := strings.Trim(u.Path, "/")
+	assetID, found := strings.CutPrefix(path, "show/mod/")
+	if !found {
+		if path == "" || strings.Contains(path, "/") {
+			return "", fmt.Errorf("%w: %s", ErrNoModID, uri)
+		}
+		return path, nil
+	}
+
+	// ModDB API accepts asset id in place of modid
+	mod, err := DB.FetchMod(assetID)
+	if err != nil {
+		return "", err
+	}
+	if len(mod.Releases) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrNoRelease, uri)
+	}
+	return mod.Releases[0].ModIDStr, nil
+}
+
+// Dependencies returns dependencies declared in modinfo.json of the release.
+// Release archive is kept in a temporary file, so Download doesn't fetch it again.
+func (upd Update) Dependencies() (map[string]string, error) {
+	if filepath.Ext(upd.Filename) != ".zip" {
+		return nil, nil
+	}
+
+	path, err := upd.fetch()
+	if err != nil {
+		return nil, fmt.Errorf("Dependencies: %w", err)
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("Dependencies: %w", err)
+	}
+	defer r.Close()
+
+	info := parseModFS(r, upd.Filename)
+	if info.Error != nil {
+		return nil, fmt.Errorf("Dependencies: %w", info.Error)
+	}
+	return info.Dependencies, nil
+}
+
+// Release archives fetched by Dependencies: url -> temporary file
+var (
+	fetchedMu sync.Mutex
+	fetched   = map[string]string{}
+)
+
+// fetch downloads the release into a temporary file, reusing earlier fetch of the same url
+func (upd Update) fetch() (string, error) {
+	fetchedMu.Lock()
+	path, ok := fetched[upd.URL]
+	fetchedMu.Unlock()
+	if ok {
+		return path, nil
+	}
+
+	body, _, err := DB.Download(upd.URL)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp("", "VSModUpdater-*-"+filepath.Base(upd.Filename))
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(tmp, body)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	fetchedMu.Lock()
+	defer fetchedMu.Unlock()
+	if path, ok := fetched[upd.URL]; ok {
+		// Fetched concurrently, keep the first file
+		os.Remove(tmp.Name())
+		return path, nil
+	}
+	fetched[upd.URL] = tmp.Name()
+	return tmp.Name(), nil
+}
+
+// open returns release contents and size, taking over the file from fetch if there is one
+func (upd Update) open() (io.ReadCloser, int64, error) {
+	fetchedMu.Lock()
+	path, ok := fetched[upd.URL]
+	delete(fetched, upd.URL)
+	fetchedMu.Unlock()
+	if !ok {
+		return DB.Download(upd.URL)
+	}
+
+	f, err := os.Open(path)
+	if err == nil {
+		var st os.FileInfo
+		st, err = f.Stat()
+		if err == nil {
+			return fetchedFile{f}, st.Size(), nil
+		}
+		f.Close()
+	}
+	os.Remove(path)
+	return DB.Download(upd.URL)
+}
+
+// fetchedFile removes the temporary file when closed
+type fetchedFile struct{ *os.File }
+
+func (f fetchedFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.Name())
+	return err
+}
+
+// RemoveFetched removes release archives fetched by Dependencies but never downloaded
+func RemoveFetched() {
+	fetchedMu.Lock()
+	defer fetchedMu.Unlock()
+	for uri, path := range fetched {
+		os.Remove(path)
+		delete(fetched, uri)
+	}
+}
+
+// Download saves the release into upd.Dir.
+// File is downloaded to a temporary file and verified before it's moved into place,
+// so interrupted downloads never leave a truncated mod behind.
 func (upd Update) Download() error {
-	req, err := http.NewRequest(http.MethodGet, upd.URL, nil)
+	body, size, err := upd.open()
 	if err != nil {
-		return err
+		return fmt.Errorf("Download: %w", err)
 	}
+	defer body.Close()
 
-	// Make sure queries are escaped
-	req.URL.RawQuery = url.QueryEscape(req.URL.RawQuery)
+	tmp, err := os.CreateTemp(filepath.Dir(upd.Path()), "."+upd.Filename+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("Download: %w", err)
+	}
+	defer os.Remove(tmp.Name())
 
-	resp, err := http.DefaultClient.Do(req)
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), body)
+	if err == nil {
+		// CreateTemp makes the file private, mods have to be readable by the game
+		err = tmp.Chmod(0o644)
+	}
+	tmp.Close()
 	if err != nil {
-		return err
+		return fmt.Errorf("Download: %w", err)
+	}
+
+	if size >= 0 && n != size {
+		return fmt.Errorf("Download: %w: got %d of %d bytes", ErrIncomplete, n, size)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Download: status: %s", resp.Status)
+	if sum := hex.EncodeToString(h.Sum(nil)); upd.SHA256 != "" && sum != upd.SHA256 {
+		return fmt.Errorf("Download: %w: expected %s, got %s", ErrHashMismatch, upd.SHA256, sum)
 	}
 
-	out, err := os.Create(filepath.Join(config.ModPath, upd.Filename))
+	err = upd.verify(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("Download: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), upd.Path())
+}
+
+// Path returns location of the downloaded release
+func (upd Update) Path() string {
+	return filepath.Join(cmp.Or(upd.Dir, config.ModPath), upd.Filename)
+}
+
+// verify checks that downloaded file is a valid mod archive matching update ModID.
+// Non-zip releases (e.g. .cs, .dll) can't be verified.
+func (upd Update) verify(path string) error {
+	if filepath.Ext(upd.Filename) != ".zip" {
+		return nil
+	}
+
+	r, err := zip.OpenReader(path)
 	if err != nil {
 		return err
 	}
-	defer out.Close()
+	defer r.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	info := parseModFS(r, path)
+	if info.Error != nil {
+		return fmt.Errorf("modinfo.json: %w", info.Error)
+	}
+
+	// Older mods may derive ModID from the name
+	if upd.ModID != "" && info.ModID != "" && !strings.EqualFold(upd.ModID, info.ModID) {
+		return fmt.Errorf("%w: expected %s, got %s", ErrModIDMismatch, upd.ModID, info.ModID)
+	}
+	return nil
 }
@@ -0,0 +1,103 @@
+package mod
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+var (
+	ErrModified      = errors.New("modified")
+	ErrCorrupted     = errors.New("corrupted")
+	ErrUnknownOrigin = errors.New("unknown origin")
+	ErrUnverifiable  = errors.New("folder mods can't be verified")
+)
+
+// Verify compares installed file with the ModDB release of the same version.
+// Returns the release as Update with reference hash, so a clean copy can be downloaded.
+func (i *Info) Verify() (Update, error) {
+	if i.Error != nil {
+		return Update{}, fmt.Errorf("%w: %w", ErrCorrupted, i.Error)
+	}
+	if i.ModID == "" {
+		return Update{}, fmt.Errorf("%w: %w", ErrUnknownOrigin, ErrNoModID)
+	}
+	if filepath.Ext(i.Path) != ".zip" {
+		return Update{}, ErrUnverifiable
+	}
+
+	mod, err := i.FetchMod()
+	if err != nil {
+		return Update{}, fmt.Errorf("Info.Verify: %w", err)
+	}
+
+	for _, rel := range mod.Releases {
+		if rel.ModVersion.Compare(i.Version) != 0 {
+			continue
+		}
+
+		upd := Update{
+			ModID:       i.ModID,
+			Name:        mod.Name,
+			URL:         rel.Mainfile,
+			Version:     rel.ModVersion,
+			Filename:    rel.Filename,
+			GameVersion: GetLatestVersion(rel.Tags),
+			Dir:         i.Dir,
+		}
+
+		upd.SHA256, err = ReferenceHash(rel.Mainfile)
+		if err != nil {
+			return upd, fmt.Errorf("Info.Verify: %w", err)
+		}
+
+		sum, err := HashFile(i.Path)
+		if err != nil {
+			return upd, fmt.Errorf("Info.Verify: %w", err)
+		}
+
+		if sum != upd.SHA256 {
+			return upd, ErrModified
+		}
+		return upd, nil
+	}
+	return Update{}, fmt.Errorf("%w: no release %s@%s", ErrUnknownOrigin, i.ModID, i.Version)
+}
+
+// ReferenceHash returns sha256 of the release file.
+// Release files don't change, hashes are cached without expiration.
+func ReferenceHash(uri string) (string, error) {
+	key := sha256.Sum256([]byte(uri))
+	path := filepath.Join(config.CachePath, "hashes", hex.EncodeToString(key[:])+".sha256")
+	if config.CachePath != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.TrimSpace(string(data)), nil
+		}
+	}
+
+	body, _, err := DB.Download(uri)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, body)
+	if err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	// Cache is best effort
+	if config.CachePath != "" && os.MkdirAll(filepath.Dir(path), 0o755) == nil {
+		os.WriteFile(path, []byte(sum), 0o644)
+	}
+	return sum, nil
+}
@@ -0,0 +1,61 @@
+package modes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+// Changelog prints changelogs of releases newer than the installed version.
+// All releases are shown if the mod is not installed.
+func Changelog(modID string) {
+	m := &mod.Info{ModID: modID}
+	if installed, err := loadMods(); err == nil {
+		if i := findInstalled(installed, modID); i != nil {
+			m = i
+		}
+	}
+
+	logs, err := m.Changelog(mod.SemVer{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	switch {
+	case len(logs) == 0 && !m.Version.IsValid():
+		fmt.Println("No releases found for", modID)
+		return
+	case len(logs) == 0:
+		fmt.Printf("%s is up to date\n", m)
+		return
+	}
+
+	if m.Version.IsValid() {
+		fmt.Printf(":: Changes since %s:\n", m)
+	} else {
+		fmt.Printf(":: Changes of %s:\n", modID)
+	}
+	printLogs(logs, " ")
+}
+
+// printChangelog prints changelogs of releases between installed version and to
+func printChangelog(m *mod.Info, to mod.SemVer, indent string) {
+	logs, err := m.Changelog(to)
+	if err != nil {
+		fmt.Printf("%s%v\n", indent, err)
+		return
+	}
+	printLogs(logs, indent)
+}
+
+func printLogs(logs []mod.Changelog, indent string) {
+	for _, log := range logs {
+		fmt.Printf("%s%s (%s)\n", indent, log.Version, log.Created)
+		for line := range strings.Lines(log.Text) {
+			fmt.Printf("%s  %s", indent, line)
+		}
+		fmt.Println()
+	}
+}
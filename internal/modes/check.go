@@ -0,0 +1,65 @@
+package modes
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+// Exit codes of the check mode
+const (
+	ExitUpToDate = 0
+	ExitError    = 1
+	ExitUpdates  = 100
+)
+
+// Check reports available updates without touching any files.
+// Returns exit code, errors take precedence over available updates.
+func Check() int {
+	mods, err := loadMods()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error loading mods:", err)
+		return ExitError
+	}
+
+	if config.Output != "text" {
+		records, err := printRecords(mods)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitError
+		}
+
+		code := ExitUpToDate
+		for _, r := range records {
+			switch r.Outcome {
+			case OutcomeError:
+				return ExitError
+			case OutcomeUpdate:
+				code = ExitUpdates
+			}
+		}
+		return code
+	}
+
+	printGameVersion()
+	fmt.Println(":: Searching for updates...")
+
+	res := checkAll(mods)
+	res.Print()
+
+	for _, m := range res.updates {
+		fmt.Printf(" %s (%s -> %s) - %s\n", m.Name, m.Version, m.Update.Version, m.Page())
+		if config.Changelogs {
+			printChangelog(m.Info, m.Update.Version, "    ")
+		}
+	}
+
+	switch {
+	case len(res.errors) > 0:
+		return ExitError
+	case len(res.updates) > 0:
+		return ExitUpdates
+	}
+	return ExitUpToDate
+}
@@ -0,0 +1,84 @@
+package modes
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+// Doctor reports mods installed more than once and offers to remove the extra copies
+func Doctor() {
+	mods, err := loadMods()
+	if err != nil {
+		fmt.Println("Error loading mods:", err)
+		return
+	}
+
+	run := backup.NewRun("doctor")
+	defer saveRun(run)
+
+	if len(mod.Duplicates(mods)) == 0 {
+		fmt.Println("No duplicate mods found")
+		return
+	}
+	fixDuplicates(mods, run)
+}
+
+// fixDuplicates reports duplicate mods and moves copies other than the newest to the run backup.
+// Returns mods without the extra copies, which are left out even if they were kept.
+func fixDuplicates(mods []*mod.Info, run *backup.Run) []*mod.Info {
+	dups := mod.Duplicates(mods)
+	if len(dups) == 0 {
+		return mods
+	}
+
+	extra := []*mod.Info{}
+	fmt.Println(":: Duplicate mods found:")
+	for _, d := range dups {
+		fmt.Printf(" %s (%s):\n", d.ModID, d.Reason)
+		fmt.Printf("   keep   %s - %s\n", d.Keep.Version, d.Keep.Path)
+		for _, m := range d.Extra {
+			fmt.Printf("   remove %s - %s\n", m.Version, m.Path)
+		}
+		extra = append(extra, d.Extra...)
+	}
+	mods = slices.DeleteFunc(mods, func(m *mod.Info) bool { return slices.Contains(extra, m) })
+
+	if !confirm("\n=> Move duplicates to backups? [y/N] ") {
+		fmt.Println(":: Duplicates are left in place and skipped")
+		fmt.Println()
+		return mods
+	}
+
+	for _, m := range extra {
+		fmt.Printf(" %s - %s", m, m.Path)
+		if config.DryRun {
+			fmt.Println(" - OK")
+			continue
+		}
+
+		oldPath := m.Path
+		dir, err := run.Dir()
+		if err == nil {
+			err = m.Backup(dir)
+		}
+		if err != nil {
+			fmt.Println(" -", err)
+			continue
+		}
+
+		fmt.Println(" - OK")
+		run.Add(backup.Entry{
+			ModID:      m.ModID,
+			Name:       m.Name,
+			OldVersion: m.Version.String(),
+			OldPath:    oldPath,
+			BackupPath: m.Path,
+		})
+	}
+	fmt.Println()
+	return mods
+}
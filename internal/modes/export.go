@@ -25,7 +25,7 @@ func Export(output string) {
 		return
 	}
 
-	mods, err := mod.InfoFromPath(config.ModPath)
+	mods, err := loadMods()
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -36,6 +36,11 @@ func Export(output string) {
 		return
 	}
 
+	if filepath.Ext(output) == ".json" {
+		exportLockfile(output, mods)
+		return
+	}
+
 	modlist := make([]string, 0, len(mods))
 
 	for _, m := range mods {
@@ -43,7 +48,11 @@ func Export(output string) {
 			fmt.Println(m, "-", mod.ErrNoModID)
 			continue
 		}
-		modlist = append(modlist, m.ModID+"@"+m.Version.String())
+		line := m.ModID + "@" + m.Version.String()
+		if m.Disabled {
+			line += " disabled"
+		}
+		modlist = append(modlist, line)
 	}
 
 	err = os.WriteFile(output, []byte(strings.Join(modlist, "\n")), 0o644)
@@ -55,3 +64,34 @@ func Export(output string) {
 	abspath, _ := filepath.Abs(output)
 	fmt.Println("Finished export", cmp.Or(abspath, output))
 }
+
+// exportLockfile writes lockfile with release metadata and hashes of installed mods
+func exportLockfile(output string, mods []*mod.Info) {
+	type locked struct {
+		Entry mod.LockEntry
+		Err   error
+	}
+
+	results := Parallel(mods, func(m *mod.Info) (res locked) {
+		res.Entry, res.Err = m.Lock()
+		return
+	})
+
+	lock := &mod.Lockfile{Version: mod.LockfileVersion}
+	for idx, m := range mods {
+		if err := results[idx].Err; err != nil {
+			fmt.Println(m, "-", err)
+			continue
+		}
+		lock.Mods = append(lock.Mods, results[idx].Entry)
+	}
+
+	err := lock.Write(output)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	abspath, _ := filepath.Abs(output)
+	fmt.Println("Finished export", cmp.Or(abspath, output))
+}
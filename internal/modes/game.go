@@ -0,0 +1,26 @@
+package modes
+
+import (
+	"fmt"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+// printGameVersion prints the target game version used for update selection
+func printGameVersion() {
+	if config.GameVersion != "" {
+		fmt.Println(":: Target game version:", config.GameVersion)
+		return
+	}
+
+	installed, err := mod.InstalledGame()
+	if err != nil {
+		// Only report failures if user explicitly pointed to the game
+		if config.GamePath != "" {
+			fmt.Println(":: Game version detection failed:", err)
+		}
+		return
+	}
+	fmt.Println(":: Installed game version:", installed)
+}
@@ -2,10 +2,14 @@ package modes
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
 	"github.com/rafalb8/VSModUpdater/v2/internal/config"
 	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
 )
@@ -24,34 +28,280 @@ func Import(input string) {
 		return
 	}
 
-	f, err := os.Open(input)
+	data, err := os.ReadFile(input)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	reader := bufio.NewReader(f)
 
-	line, _, err := reader.ReadLine()
-	for ; err == nil; line, _, err = reader.ReadLine() {
-		update, err := mod.UpdateFromString(string(line))
+	var updates []mod.Update
+	isLockfile := mod.IsLockfile(data)
+	if isLockfile {
+		updates, err = importLockfile(data)
+	} else {
+		updates, err = importModList(data)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	installed, err := loadMods()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	run := backup.NewRun("import")
+	defer saveRun(run)
+
+	disabled := []*mod.Info{}
+	for _, update := range updates {
+		err = importUpdate(run, update, installed)
 		if err != nil {
+			fmt.Println("FAIL")
 			fmt.Println(err)
 			continue
 		}
+		fmt.Println("SUCCESS")
 
-		fmt.Printf("Downloading %s@%s - ", update.Name, update.Version.String())
-		err = update.Download()
+		if update.Disabled {
+			disabled = append(disabled, &mod.Info{ModID: update.ModID, Version: update.Version})
+		}
+	}
+
+	if len(disabled) > 0 {
+		fmt.Printf("Disabling %d mods - ", len(disabled))
+		err = mod.DisableMods(disabled...)
+		if err != nil {
+			fmt.Println("FAIL")
+			fmt.Println(err)
+		} else {
+			fmt.Println("SUCCESS")
+		}
+	}
+
+	// Lockfile is the complete mod set
+	if isLockfile {
+		removeUnlocked(run, installed, updates)
+	}
+	fmt.Println("Finished import")
+}
+
+// removeUnlocked lists installed mods missing from the lockfile and offers to move them to the run backup
+func removeUnlocked(run *backup.Run, installed []*mod.Info, locked []mod.Update) {
+	inLockfile := map[string]bool{}
+	for _, upd := range locked {
+		inLockfile[strings.ToLower(upd.ModID)] = true
+	}
+
+	extra := slices.DeleteFunc(slices.Clone(installed), func(m *mod.Info) bool {
+		return m.ModID != "" && inLockfile[strings.ToLower(m.ModID)]
+	})
+	if len(extra) == 0 {
+		return
+	}
+
+	fmt.Println("Mods not in the lockfile:")
+	for _, m := range extra {
+		fmt.Printf(" %s - %s\n", m, m.Path)
+	}
+
+	if !confirm("=> Move them to backups? [y/N] ") {
+		return
+	}
+
+	dir, err := run.Dir()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, m := range extra {
+		fmt.Printf("Moving %s to backups - ", m)
+		oldPath := m.Path
+		err = m.Backup(dir)
 		if err != nil {
 			fmt.Println("FAIL")
 			fmt.Println(err)
 			continue
 		}
 		fmt.Println("SUCCESS")
+
+		run.Add(backup.Entry{
+			ModID:      m.ModID,
+			Name:       m.Name,
+			OldVersion: m.Version.String(),
+			OldPath:    oldPath,
+			BackupPath: m.Path,
+		})
+	}
+}
+
+// importUpdate downloads the update, unless the same release is already installed.
+// Other installed copies of the mod are replaced, changes are recorded in the run.
+func importUpdate(run *backup.Run, update mod.Update, installed []*mod.Info) error {
+	found := false
+	replaced := []*mod.Info{}
+	for _, m := range installed {
+		if m.ModID != update.ModID || m.Error != nil {
+			continue
+		}
+		if !found && isImported(m, update) {
+			found = true
+			continue
+		}
+		replaced = append(replaced, m)
+	}
+
+	if len(replaced) == 0 {
+		if found {
+			fmt.Printf("Skipping %s@%s - ", update.Name, update.Version)
+			return nil
+		}
+
+		fmt.Printf("Downloading %s@%s - ", update.Name, update.Version)
+		err := update.Download()
+		if err != nil {
+			return err
+		}
+		run.Add(newEntry(&mod.Info{}, update, ""))
+		return nil
+	}
+
+	// Backup before download. New file might have the same filename
+	dir, err := run.Dir()
+	if err != nil {
+		return err
+	}
+
+	oldPaths := make([]string, 0, len(replaced))
+	for _, m := range replaced {
+		oldPath := m.Path
+		err = m.Backup(dir)
+		if err != nil {
+			restoreAll(replaced[:len(oldPaths)])
+			return err
+		}
+		oldPaths = append(oldPaths, oldPath)
+	}
+
+	if found {
+		fmt.Printf("Removing other copies of %s@%s - ", update.Name, update.Version)
+	} else {
+		// Replacement stays in the directory of the replaced mod
+		update.Dir = replaced[0].Dir
+
+		fmt.Printf("Downloading %s@%s - ", update.Name, update.Version)
+		err = update.Download()
+		if err != nil {
+			restoreAll(replaced)
+			return err
+		}
+	}
+
+	for idx, m := range replaced {
+		entry := backup.Entry{
+			ModID:      m.ModID,
+			Name:       m.Name,
+			OldVersion: m.Version.String(),
+			OldPath:    oldPaths[idx],
+		}
+		if idx == 0 && !found {
+			entry = newEntry(m, update, oldPaths[idx])
+		}
+
+		if config.Backup {
+			entry.BackupPath = m.Path
+			run.Add(entry)
+			continue
+		}
+		run.Add(entry)
+
+		// Remove the backup
+		err = os.RemoveAll(m.Path)
+		if err != nil {
+			return fmt.Errorf("cleanup: %w", err)
+		}
+	}
+	return nil
+}
+
+// isImported reports whether m is the release of the update.
+// Folder mods have no file hash, matching version is enough.
+func isImported(m *mod.Info, update mod.Update) bool {
+	if m.Version.Compare(update.Version) != 0 {
+		return false
+	}
+
+	stat, err := os.Stat(m.Path)
+	if err != nil {
+		return false
+	}
+	if update.SHA256 == "" || stat.IsDir() {
+		return true
+	}
+
+	sum, err := mod.HashFile(m.Path)
+	return err == nil && sum == update.SHA256
+}
+
+// restoreAll moves backed up mods back to their mod directories
+func restoreAll(mods []*mod.Info) {
+	for _, m := range mods {
+		err := m.Restore()
+		if err != nil {
+			fmt.Println("Restore:", err)
+		}
+	}
+}
+
+// importModList returns updates from modid@version lines, including missing dependencies
+func importModList(data []byte) ([]mod.Update, error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+
+	updates := []mod.Update{}
+	line, _, err := reader.ReadLine()
+	for ; err == nil; line, _, err = reader.ReadLine() {
+		update, err := mod.UpdateFromString(string(line))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		updates = append(updates, update)
 	}
 
 	if err != io.EOF {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
-	fmt.Println("Finished import")
+
+	installed, err := loadMods()
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := mod.Resolve(installed, updates)
+	if err != nil {
+		return nil, fmt.Errorf("%w\nImport aborted, dependencies can't be satisfied", err)
+	}
+
+	for _, dep := range deps {
+		fmt.Printf("Adding dependency %s@%s - required by %s\n", dep.Name, dep.Version, dep.RequiredBy)
+	}
+	return append(updates, deps...), nil
+}
+
+// importLockfile returns updates for locked releases.
+// Lockfile is a complete mod set, so dependencies are not resolved.
+func importLockfile(data []byte) ([]mod.Update, error) {
+	lock, err := mod.ParseLockfile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]mod.Update, 0, len(lock.Mods))
+	for _, entry := range lock.Mods {
+		updates = append(updates, entry.Update())
+	}
+	return updates, nil
 }
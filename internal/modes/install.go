@@ -0,0 +1,109 @@
+package modes
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+func Install(targets []string) {
+	if len(targets) == 0 {
+		fmt.Println("No mods to install, usage: --install modid modid@version modid@latest url...")
+		return
+	}
+
+	err := os.MkdirAll(config.ModPath, 0o755)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	installed, err := loadMods()
+	if err != nil {
+		fmt.Println("Error loading mods:", err)
+		return
+	}
+
+	printGameVersion()
+	fmt.Println(":: Searching for releases...")
+
+	selected := []update{}
+	for _, target := range targets {
+		upd, err := mod.UpdateFromTarget(target)
+		if err != nil {
+			fmt.Printf(" %s - %v\n", target, err)
+			continue
+		}
+
+		info := &mod.Info{ModID: upd.ModID, Name: upd.Name}
+		if m := findInstalled(installed, upd.ModID); m != nil {
+			if m.Version.Compare(upd.Version) == 0 {
+				fmt.Printf(" %s - already installed\n", m)
+				continue
+			}
+
+			if !confirm(fmt.Sprintf(" %s is installed, replace with %s? [y/N] ", m, upd.Version)) {
+				fmt.Printf(" %s - skipped, different version is installed\n", m)
+				continue
+			}
+			info = m
+
+			// Replacement stays in the directory of the installed mod
+			upd.Dir = m.Dir
+		}
+
+		fmt.Printf(" %s@%s\n", upd.Name, upd.Version)
+		selected = append(selected, update{info, upd})
+	}
+	fmt.Println()
+
+	if len(selected) == 0 {
+		return
+	}
+
+	// Release archives can't be inspected without network
+	if !config.Offline {
+		fmt.Println(":: Resolving dependencies...")
+		selected, err = resolveDependencies(installed, selected)
+		if err != nil {
+			for line := range strings.Lines(err.Error()) {
+				fmt.Print(" ", line)
+			}
+			fmt.Println("\n:: Install aborted, dependencies can't be satisfied")
+			return
+		}
+		fmt.Println()
+	}
+
+	run := backup.NewRun("install")
+	defer saveRun(run)
+
+	fmt.Println(":: Installing mods...")
+	installUpdates(run, selected)
+}
+
+// findInstalled returns installed mod with modid
+func findInstalled(installed []*mod.Info, modID string) *mod.Info {
+	for _, m := range installed {
+		if m.Error == nil && strings.EqualFold(m.ModID, modID) {
+			return m
+		}
+	}
+	return nil
+}
+
+// confirm asks yes/no question, answer defaults to no. Always true with --no-confirm.
+func confirm(prompt string) bool {
+	if config.NoConfirm {
+		return true
+	}
+
+	answer := ""
+	fmt.Print(prompt)
+	fmt.Scanln(&answer)
+	return len(answer) > 0 && answer[0]|' ' == 'y'
+}
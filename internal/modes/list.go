@@ -2,6 +2,7 @@ package modes
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/rafalb8/VSModUpdater/v2/internal/config"
@@ -9,30 +10,58 @@ import (
 )
 
 func List() {
-	mods, err := mod.InfoFromPath(config.ModPath)
+	mods, err := loadMods()
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
+	if config.Output != "text" {
+		_, err = printRecords(mods)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return
+	}
+
 	if len(mods) == 0 {
 		fmt.Println("No Mods found")
 		return
 	}
 
-	sep := strings.Repeat("=", 80)
-	for _, m := range mods {
+	printGameVersion()
+
+	details := Parallel(mods, func(m *mod.Info) string {
 		m.FetchMod() // Cache AssetID for m.Page()
+		return m.Details()
+	})
+
+	sep := strings.Repeat("=", 80)
+	for idx, m := range mods {
 		fmt.Println(sep)
 
 		if m.Error != nil {
 			fmt.Print("\033[0;31m") // Red
-			fmt.Println(m.Details())
+			fmt.Println(details[idx])
+			fmt.Print("\033[0m") // Reset
+			continue
+		}
+
+		if m.Disabled {
+			fmt.Print("\033[0;90m") // Gray
+			fmt.Println(details[idx])
+			fmt.Print("\033[0m") // Reset
+			continue
+		}
+
+		if !m.IsGameSupported() {
+			fmt.Print("\033[0;33m") // Yellow
+			fmt.Println(details[idx])
 			fmt.Print("\033[0m") // Reset
 			continue
 		}
 
-		fmt.Println(m.Details())
+		fmt.Println(details[idx])
 	}
 	fmt.Println(sep)
 }
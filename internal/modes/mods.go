@@ -0,0 +1,52 @@
+package modes
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+// loadMods returns mods from every mod directory, in server mode
+// from mod directories of serverconfig.json
+func loadMods() ([]*mod.Info, error) {
+	dirs := config.ModPaths
+	if config.Server {
+		var (
+			unresolved []string
+			err        error
+		)
+		dirs, unresolved, err = mod.ServerModPaths()
+		if err != nil {
+			return nil, err
+		}
+
+		// Warnings go to stderr, so machine readable output stays valid
+		for _, dir := range unresolved {
+			fmt.Fprintf(os.Stderr, "Skipping mod directory %s: relative to game installation, which wasn't found (see --game-path)\n", dir)
+		}
+	}
+
+	mods := []*mod.Info{}
+	for _, dir := range dirs {
+		found, err := mod.InfoFromPath(dir)
+		if config.Server && errors.Is(err, fs.ErrNotExist) {
+			// Configured directories don't have to exist
+			fmt.Fprintln(os.Stderr, "Skipping missing mod directory:", dir)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		mods = append(mods, found...)
+	}
+	return mods, nil
+}
+
+// isClientOnly reports whether the mod is skipped as client-only in server mode
+func isClientOnly(m *mod.Info) bool {
+	return config.Server && m.Error == nil && m.IsClientOnly()
+}
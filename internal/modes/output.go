@@ -0,0 +1,117 @@
+package modes
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+// Outcome of the update check
+const (
+	OutcomeUpdate          = "update"
+	OutcomeUpToDate        = "up-to-date"
+	OutcomePreReleaseSkip  = "pre-release-skipped"
+	OutcomeUnstableSkip    = "unstable-skipped"
+	OutcomeGameVersionSkip = "game-incompatible"
+	OutcomeConstraintSkip  = "held-back"
+	OutcomeIgnored         = "ignored"
+	OutcomeDisabled        = "disabled"
+	OutcomeClientOnly      = "client-only"
+	OutcomeError           = "error"
+)
+
+// record is machine readable result of the update check
+type record struct {
+	Path        string `json:"path"`
+	ModID       string `json:"modid,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Version     string `json:"version,omitempty"`     // Installed version
+	Latest      string `json:"latest,omitempty"`      // Latest release found by the check
+	GameVersion string `json:"gameVersion,omitempty"` // Game version required by the latest release
+	Disabled    bool   `json:"disabled,omitempty"`
+	Outcome     string `json:"outcome"`
+	URL         string `json:"url,omitempty"`
+	Error       string `json:"error,omitempty"`
+
+	Changelog []mod.Changelog `json:"changelog,omitempty"` // Releases newer than installed up to latest
+}
+
+func newRecord(m *mod.Info, res checked) record {
+	r := record{
+		Path:     m.Path,
+		ModID:    m.ModID,
+		Name:     m.Name,
+		Disabled: m.Disabled,
+	}
+	if m.Error != nil {
+		r.Outcome, r.Error = OutcomeError, m.Error.Error()
+		return r
+	}
+
+	r.Version = m.Version.String()
+	r.URL = m.Page()
+	if _, ignored := config.Ignored[m.ModID]; ignored {
+		r.Outcome = OutcomeIgnored
+		return r
+	}
+	if config.SkipDisabled && m.Disabled {
+		r.Outcome = OutcomeDisabled
+		return r
+	}
+	if isClientOnly(m) {
+		r.Outcome = OutcomeClientOnly
+		return r
+	}
+
+	switch res.Err {
+	case nil:
+		r.Outcome = OutcomeUpdate
+	case mod.ErrNoUpdate:
+		r.Outcome, r.Latest = OutcomeUpToDate, r.Version
+		return r
+	case mod.ErrPreReleaseSkip:
+		r.Outcome = OutcomePreReleaseSkip
+	case mod.ErrUnstableSkip:
+		r.Outcome = OutcomeUnstableSkip
+	case mod.ErrGameVersionSkip:
+		r.Outcome = OutcomeGameVersionSkip
+	case mod.ErrConstraintSkip:
+		r.Outcome = OutcomeConstraintSkip
+	default:
+		r.Outcome, r.Error = OutcomeError, res.Err.Error()
+		return r
+	}
+
+	r.Latest = res.Update.Version.String()
+	r.GameVersion = res.Update.GameVersion.String()
+	r.Changelog, _ = m.Changelog(res.Update.Version)
+	return r
+}
+
+// printRecords checks mods for updates and prints results in config.Output format.
+// ndjson records are printed as soon as they are ready.
+func printRecords(mods []*mod.Info) ([]record, error) {
+	records := ParallelSeq(mods, func(m *mod.Info) record {
+		return newRecord(m, checkMod(m))
+	})
+
+	enc := json.NewEncoder(os.Stdout)
+	all := make([]record, 0, len(mods))
+	for _, r := range records {
+		all = append(all, r)
+		if config.Output == "ndjson" {
+			err := enc.Encode(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if config.Output == "ndjson" {
+		return all, nil
+	}
+	enc.SetIndent("", "\t")
+	return all, enc.Encode(all)
+}
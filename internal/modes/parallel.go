@@ -0,0 +1,65 @@
+package modes
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+// Parallel calls fn for every item using config.Jobs workers.
+// Results keep the order of items.
+func Parallel[T, R any](items []T, fn func(T) R) []R {
+	results := make([]R, 0, len(items))
+	for _, res := range ParallelSeq(items, fn) {
+		results = append(results, res)
+	}
+	return results
+}
+
+// ParallelSeq is like Parallel, but yields results in order of items
+// as soon as they are ready.
+func ParallelSeq[T, R any](items []T, fn func(T) R) iter.Seq2[int, R] {
+	return func(yield func(int, R) bool) {
+		results := make([]R, len(items))
+		done := make([]chan struct{}, len(items))
+		for idx := range done {
+			done[idx] = make(chan struct{})
+		}
+
+		var wg sync.WaitGroup
+		defer wg.Wait()
+
+		// Remaining items are not processed once the consumer stops
+		stop := make(chan struct{})
+		defer close(stop)
+
+		jobs := make(chan int)
+		go func() {
+			defer close(jobs)
+			for idx := range items {
+				select {
+				case jobs <- idx:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		for range min(max(config.Jobs, 1), len(items)) {
+			wg.Go(func() {
+				for idx := range jobs {
+					results[idx] = fn(items[idx])
+					close(done[idx])
+				}
+			})
+		}
+
+		for idx := range items {
+			<-done[idx]
+			if !yield(idx, results[idx]) {
+				return
+			}
+		}
+	}
+}
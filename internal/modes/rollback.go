@@ -0,0 +1,158 @@
+package modes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+)
+
+func Rollback() {
+	runs, err := backup.Runs()
+	if err != nil {
+		fmt.Println("Error loading runs:", err)
+		return
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No runs recorded in", config.BackupPath)
+		return
+	}
+
+	fmt.Println(":: Recorded runs:")
+	for i, run := range runs {
+		fmt.Printf("[%d] %s - %s\n", i+1, run.Time.Format(time.DateTime), run.Mode)
+		for _, e := range run.Entries {
+			fmt.Println("    ", e)
+		}
+	}
+
+	// Latest run is restored by default
+	idx := 1
+	if !config.NoConfirm {
+		fmt.Println("\n=> Run to restore: (default 1)")
+		fmt.Print("=> ")
+
+		s := bufio.NewScanner(os.Stdin)
+		s.Scan()
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			idx, err = strconv.Atoi(text)
+			if err != nil || idx < 1 || idx > len(runs) {
+				fmt.Println("Invalid run:", text)
+				return
+			}
+		}
+	}
+	fmt.Println()
+
+	run := runs[idx-1]
+	undo := backup.NewRun("rollback")
+	defer saveRun(undo)
+
+	fmt.Printf(":: Restoring run %s...\n", run.Time.Format(time.DateTime))
+	for _, e := range slices.Backward(run.Entries) {
+		fmt.Printf(" %s", e)
+
+		if config.DryRun {
+			fmt.Println(" - OK")
+			continue
+		}
+
+		if e.OldPath != "" && e.BackupPath == "" {
+			fmt.Println(" - previous version was not backed up")
+			continue
+		}
+
+		// Move newer file aside, so the rollback can be reverted too
+		revert := backup.Entry{
+			ModID:      e.ModID,
+			Name:       e.Name,
+			OldVersion: e.NewVersion,
+			OldPath:    e.NewPath,
+			NewVersion: e.OldVersion,
+			NewPath:    e.OldPath,
+		}
+
+		if e.NewPath != "" {
+			revert.BackupPath, err = undo.Store(e.NewPath)
+			if os.IsNotExist(err) {
+				// Newer file was already removed
+				revert.BackupPath, err = "", nil
+			}
+			if err != nil {
+				fmt.Println(" -", err)
+				continue
+			}
+		}
+
+		if e.BackupPath != "" {
+			err = os.Rename(e.BackupPath, e.OldPath)
+			if err != nil {
+				fmt.Println(" -", err)
+				continue
+			}
+		}
+
+		fmt.Println(" - OK")
+		undo.Add(revert)
+	}
+}
+
+// saveRun records the run, so it can be restored with --rollback,
+// and applies backup retention policy
+func saveRun(run *backup.Run) {
+	if config.DryRun {
+		return
+	}
+
+	err := run.Save()
+	if err != nil {
+		fmt.Println("Failed to record run:", err)
+	}
+
+	// Failed updates are restored, leaving empty generation behind
+	if len(run.Entries) == 0 {
+		run.Discard()
+	}
+
+	pruneBackups(false)
+}
+
+// PruneBackups removes backup runs according to retention policy
+func PruneBackups() {
+	if !pruneBackups(true) {
+		fmt.Println("Nothing to prune in", config.BackupPath)
+	}
+}
+
+// pruneBackups removes expired backup runs, returns true if any run was expired
+func pruneBackups(verbose bool) bool {
+	runs, err := backup.Runs()
+	if err != nil {
+		fmt.Println("Error loading runs:", err)
+		return false
+	}
+
+	expired := backup.Expired(runs)
+	for _, run := range expired {
+		if verbose || config.DryRun {
+			fmt.Printf("Removing backup run %s - %s (%d mods)\n", run.Time.Format(time.DateTime), run.Mode, len(run.Entries))
+		}
+
+		if config.DryRun {
+			continue
+		}
+
+		err = run.Remove()
+		if err != nil {
+			fmt.Println("Prune:", err)
+		}
+	}
+	return len(expired) > 0
+}
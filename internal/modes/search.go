@@ -0,0 +1,111 @@
+package modes
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+// searchOrder maps --sort to ModDB orderby and the same order applied locally
+var searchOrder = map[string]struct {
+	orderBy string
+	cmp     func(a, b mod.ListedMod) int
+}{
+	"downloads": {"downloads", func(a, b mod.ListedMod) int { return cmp.Compare(b.Downloads, a.Downloads) }},
+	"trending":  {"trendingpoints", func(a, b mod.ListedMod) int { return cmp.Compare(b.TrendingPoints, a.TrendingPoints) }},
+	"updated":   {"lastreleased", func(a, b mod.ListedMod) int { return cmp.Compare(b.LastReleased, a.LastReleased) }},
+}
+
+type searchResult struct {
+	listed mod.ListedMod
+	modID  string
+	latest mod.Release
+	found  bool // Release for the game version exists
+	err    error
+}
+
+func Search(query string) {
+	order := searchOrder[config.SearchSort]
+	listed, err := mod.DB.Search(query, order.orderBy)
+	if err != nil {
+		fmt.Println("Search failed:", err)
+		return
+	}
+
+	// Tags and side are filtered locally, ModDB filters tags by id
+	listed = slices.DeleteFunc(listed, func(m mod.ListedMod) bool {
+		if len(m.ModIDStrs) == 0 {
+			return true
+		}
+		if config.SearchSide != "" && !strings.EqualFold(m.Side, config.SearchSide) {
+			return true
+		}
+		for _, tag := range config.SearchTags {
+			if !slices.ContainsFunc(m.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+				return true
+			}
+		}
+		return false
+	})
+	slices.SortStableFunc(listed, order.cmp)
+
+	installed := map[string]mod.SemVer{}
+	if mods, err := loadMods(); err == nil {
+		for _, m := range mods {
+			installed[strings.ToLower(m.ModID)] = m.Version
+		}
+	}
+
+	printGameVersion()
+	game := mod.GameVersion()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODID\tNAME\tAUTHOR\tLATEST\tINSTALLED")
+
+	count := 0
+	results := ParallelSeq(listed, func(m mod.ListedMod) (res searchResult) {
+		res.listed, res.modID = m, m.ModIDStrs[0]
+
+		// Releases are needed for the game version filter
+		details, err := mod.DB.FetchMod(res.modID)
+		if err != nil {
+			res.err = err
+			return
+		}
+		res.latest, res.found = mod.LatestRelease(details, game, config.PreRelease)
+		return
+	})
+	for _, res := range results {
+		if count >= config.SearchLimit {
+			break
+		}
+
+		latest := res.latest.ModVersion.String()
+		switch {
+		case res.err != nil:
+			latest = "error: " + res.err.Error()
+		case !res.found:
+			continue
+		}
+
+		state := "-"
+		if v, ok := installed[strings.ToLower(res.modID)]; ok {
+			state = v.String()
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", res.modID, res.listed.Name, res.listed.Author, latest, state)
+		count++
+	}
+
+	if count == 0 {
+		fmt.Println("No mods found")
+		return
+	}
+	w.Flush()
+}
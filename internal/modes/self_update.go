@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -32,7 +31,8 @@ func Self() {
 	}
 
 	fmt.Print("Checking for update - ")
-	update, err := m.CheckUpdates()
+	// VSModUpdater is not tied to a game version
+	update, err := m.CheckUpdatesFor(mod.SemVer{})
 	if err == mod.ErrNoUpdate {
 		fmt.Println("SUCCESS")
 		fmt.Println("No updates")
@@ -46,30 +46,24 @@ func Self() {
 	fmt.Println("SUCCESS")
 
 	fmt.Printf("Downloading: %s => %s - ", m.Version, update.Version)
-	resp, err := http.Get(update.URL)
+	body, _, err := mod.DB.Download(update.URL)
 	if err != nil {
 		fmt.Println("FAIL")
 		fmt.Println(err)
 		return
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Println("FAIL")
-		fmt.Println("HTTP status:", resp.Status)
-		return
-	}
+	defer body.Close()
 	fmt.Println("SUCCESS")
 
 	fmt.Print("Unzipping - ")
-	buf, err := io.ReadAll(resp.Body)
+	buf, err := io.ReadAll(body)
 	if err != nil {
 		fmt.Println("FAIL")
 		fmt.Println(err)
 		return
 	}
 
-	zipReader, err := zip.NewReader(bytes.NewReader(buf), resp.ContentLength)
+	zipReader, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
 	if err != nil {
 		fmt.Println("FAIL")
 		fmt.Println(err)
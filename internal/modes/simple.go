@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
 	"github.com/rafalb8/VSModUpdater/v2/internal/config"
 	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
 )
@@ -17,8 +19,9 @@ func Simple() {
 
 	updateAll := false
 
-	fmt.Println("Updating mods:", config.ModPath)
-	mods, err := mod.InfoFromPath(config.ModPath)
+	printGameVersion()
+	fmt.Println("Updating mods:", strings.Join(config.ModPaths, ", "))
+	mods, err := loadMods()
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -29,12 +32,28 @@ func Simple() {
 		return
 	}
 
-	for _, m := range mods {
+	run := backup.NewRun("simple")
+	defer saveRun(run)
+
+	mods = fixDuplicates(mods, run)
+
+	results := Parallel(mods, checkMod)
+	for idx, m := range mods {
 		if _, ignored := config.Ignored[m.ModID]; ignored {
 			fmt.Println(m, "- Ignore")
 			continue
 		}
 
+		if config.SkipDisabled && m.Disabled {
+			fmt.Println(m, "- Disabled")
+			continue
+		}
+
+		if isClientOnly(m) {
+			fmt.Println(m, "- Client-only")
+			continue
+		}
+
 		if m.Error != nil {
 			fmt.Print("\033[0;31m") // Red
 			fmt.Println("!!!", filepath.Base(m.Path), "- Failed:", m.Error)
@@ -42,7 +61,7 @@ func Simple() {
 			continue
 		}
 
-		update, err := m.CheckUpdates()
+		update, err := results[idx].Update, results[idx].Err
 		switch err {
 		case nil:
 		case mod.ErrNoUpdate:
@@ -54,6 +73,12 @@ func Simple() {
 		case mod.ErrUnstableSkip:
 			fmt.Println(m, "- Pre-release game version support available")
 			continue
+		case mod.ErrGameVersionSkip:
+			fmt.Println(m, "- Update", update.Version, "requires game", update.GameVersion)
+			continue
+		case mod.ErrConstraintSkip:
+			fmt.Println(m, "- Update", update.Version, "held back by constraint", config.Mods[m.ModID].Version)
+			continue
 		default:
 			fmt.Println(m, "-", err)
 			continue
@@ -79,7 +104,11 @@ func Simple() {
 		}
 
 		// Backup before download. New file might have the same filename
-		err = m.Backup()
+		oldPath := m.Path
+		dir, err := run.Dir()
+		if err == nil {
+			err = m.Backup(dir)
+		}
 		if err != nil {
 			fmt.Println(m, "- Backup failed:", err)
 			continue
@@ -101,10 +130,15 @@ func Simple() {
 			continue
 		}
 		fmt.Println("SUCCESS")
+		keepDisabled(m, update)
 
+		entry := newEntry(m, update, oldPath)
 		if config.Backup {
+			entry.BackupPath = m.Path
+			run.Add(entry)
 			continue
 		}
+		run.Add(entry)
 
 		// Remove the backup
 		fmt.Printf("Removing %s - ", m)
@@ -118,4 +152,7 @@ func Simple() {
 	}
 
 	fmt.Println("Finished update")
+	if n := mod.Retries(); n > 0 {
+		fmt.Println("Retried", n, "ModDB requests")
+	}
 }
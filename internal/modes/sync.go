@@ -0,0 +1,189 @@
+package modes
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+// serverMod is a mod of the server mod set
+type serverMod struct {
+	Side             mod.AppSide
+	RequiredOnClient bool
+	Update           mod.Update // Release is resolved later if URL is empty
+}
+
+// neededOnClient reports whether clients must have the mod to join
+func (s serverMod) neededOnClient() bool {
+	return s.Side != mod.Server && s.RequiredOnClient
+}
+
+// SyncFrom makes local mods match the server mod set from a directory, lockfile or mod list.
+// Purely client-side mods are left alone.
+func SyncFrom(source string) {
+	server, err := readServerMods(source)
+	if err != nil {
+		fmt.Println("Error loading server mods:", err)
+		return
+	}
+
+	installed, err := loadMods()
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Println("Error loading mods:", err)
+		return
+	}
+
+	printGameVersion()
+	fmt.Println(":: Comparing mods with", source)
+
+	selected := []update{}
+	inServerSet := map[string]bool{}
+	for _, s := range server {
+		inServerSet[strings.ToLower(s.Update.ModID)] = true
+		if !s.neededOnClient() {
+			continue
+		}
+
+		local := findInstalled(installed, s.Update.ModID)
+		if local != nil && local.Version.Compare(s.Update.Version) == 0 {
+			continue
+		}
+
+		info := &mod.Info{ModID: s.Update.ModID, Name: s.Update.Name}
+		if local != nil {
+			info = local
+			fmt.Printf(" %s - server has %s\n", local, s.Update.Version)
+		} else {
+			fmt.Printf(" %s@%s - missing\n", s.Update.Name, s.Update.Version)
+		}
+		selected = append(selected, update{info, s.Update})
+	}
+
+	for _, m := range installed {
+		if m.Error != nil || m.IsClientOnly() || inServerSet[strings.ToLower(m.ModID)] {
+			continue
+		}
+		fmt.Printf(" %s - not on server, would be rejected\n", m)
+	}
+
+	if len(selected) == 0 {
+		fmt.Println(":: Server mods are installed")
+		return
+	}
+	fmt.Println()
+
+	if !confirm(fmt.Sprintf("=> Install %d mods at server versions? [y/N] ", len(selected))) {
+		return
+	}
+
+	// Releases of mods read from a directory are not known yet
+	resolved := Parallel(selected, func(u update) checked {
+		if u.Update.URL != "" {
+			return checked{Update: u.Update}
+		}
+		upd, err := mod.UpdateFromString(u.Update.ModID + "@" + u.Update.Version.String())
+		return checked{upd, err}
+	})
+
+	updates := make([]update, 0, len(selected))
+	for idx, u := range selected {
+		if err := resolved[idx].Err; err != nil {
+			fmt.Printf(" %s@%s - %v\n", u.Update.Name, u.Update.Version, err)
+			continue
+		}
+		upd := resolved[idx].Update
+		upd.Dir = u.Dir
+		updates = append(updates, update{u.Info, upd})
+	}
+
+	if len(updates) == 0 {
+		return
+	}
+
+	err = os.MkdirAll(config.ModPath, 0o755)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	run := backup.NewRun("sync")
+	defer saveRun(run)
+
+	fmt.Println(":: Syncing mods...")
+	installUpdates(run, updates)
+}
+
+// readServerMods reads server mod set from a mod directory, lockfile or modid@version list
+func readServerMods(source string) ([]serverMod, error) {
+	stat, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+
+	mods := []serverMod{}
+	if stat.IsDir() {
+		infos, err := mod.InfoFromPath(source)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range infos {
+			if m.Error != nil {
+				continue
+			}
+			mods = append(mods, serverMod{
+				Side:             m.Side,
+				RequiredOnClient: bool(m.RequiredOnClient),
+				Update:           mod.Update{ModID: m.ModID, Name: m.Name, Version: m.Version},
+			})
+		}
+		return mods, nil
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if mod.IsLockfile(data) {
+		lock, err := mod.ParseLockfile(data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range lock.Mods {
+			upd := entry.Update()
+			upd.Disabled = false
+			mods = append(mods, serverMod{Side: entry.Side, RequiredOnClient: true, Update: upd})
+		}
+		return mods, nil
+	}
+
+	// Mod list doesn't say which side the mods are for
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+
+		modID, rest, _ := strings.Cut(line, "@")
+		version, _, _ := strings.Cut(rest, " ")
+		v, err := mod.NewSemVer(version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", line, err)
+		}
+		mods = append(mods, serverMod{
+			Side:             mod.Universal,
+			RequiredOnClient: true,
+			Update:           mod.Update{ModID: modID, Name: modID, Version: v},
+		})
+	}
+	return mods, s.Err()
+}
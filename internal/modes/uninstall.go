@@ -0,0 +1,102 @@
+package modes
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+func Uninstall(modIDs []string) {
+	if len(modIDs) == 0 {
+		fmt.Println("No mods to uninstall, usage: --uninstall modid...")
+		return
+	}
+
+	installed, err := loadMods()
+	if err != nil {
+		fmt.Println("Error loading mods:", err)
+		return
+	}
+
+	selected := []*mod.Info{}
+	for _, modID := range modIDs {
+		m := findInstalled(installed, modID)
+		if m == nil {
+			fmt.Printf(" %s - not installed\n", modID)
+			continue
+		}
+		if !slices.Contains(selected, m) {
+			selected = append(selected, m)
+		}
+	}
+
+	run := backup.NewRun("uninstall")
+	defer saveRun(run)
+
+	fmt.Println(":: Uninstalling mods...")
+	for _, m := range selected {
+		if dependents := requiredBy(installed, selected, m.ModID); len(dependents) > 0 {
+			fmt.Printf(" %s is required by %s\n", m, strings.Join(dependents, ", "))
+			if !confirm(" Remove anyway? [y/N] ") {
+				fmt.Printf(" %s - skipped\n", m)
+				continue
+			}
+		}
+
+		fmt.Printf(" %s", m)
+		if config.DryRun {
+			fmt.Println(" - OK")
+			continue
+		}
+
+		entry := backup.Entry{
+			ModID:      m.ModID,
+			Name:       m.Name,
+			OldVersion: m.Version.String(),
+			OldPath:    m.Path,
+		}
+
+		if config.Backup {
+			dir, err := run.Dir()
+			if err == nil {
+				err = m.Backup(dir)
+			}
+			if err != nil {
+				fmt.Println(" -", err)
+				continue
+			}
+			entry.BackupPath = m.Path
+		} else {
+			err = os.RemoveAll(m.Path)
+			if err != nil {
+				fmt.Println(" -", err)
+				continue
+			}
+		}
+
+		fmt.Println(" - OK")
+		run.Add(entry)
+	}
+}
+
+// requiredBy returns names of installed mods depending on modID, except mods being removed
+func requiredBy(installed, removed []*mod.Info, modID string) []string {
+	names := []string{}
+	for _, m := range installed {
+		if m.Error != nil || slices.Contains(removed, m) {
+			continue
+		}
+
+		deps := slices.Collect(maps.Keys(m.Dependencies))
+		if slices.ContainsFunc(deps, func(dep string) bool { return strings.EqualFold(dep, modID) }) {
+			names = append(names, m.String())
+		}
+	}
+	return names
+}
@@ -6,7 +6,10 @@ import (
 	"iter"
 	"os"
 	"runtime"
+	"slices"
+	"strings"
 
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
 	"github.com/rafalb8/VSModUpdater/v2/internal/config"
 	"github.com/rafalb8/VSModUpdater/v2/internal/filter"
 	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
@@ -17,7 +20,47 @@ type update struct {
 	Update mod.Update
 }
 
+type checked struct {
+	Update mod.Update
+	Err    error
+}
+
+// checkMod checks mod for updates, skipping ignored and invalid mods.
+// Safe to call concurrently for different mods.
+func checkMod(m *mod.Info) (res checked) {
+	if _, ignored := config.Ignored[m.ModID]; ignored || m.Error != nil {
+		return
+	}
+
+	if config.SkipDisabled && m.Disabled {
+		return
+	}
+
+	if isClientOnly(m) {
+		return
+	}
+
+	res.Update, res.Err = m.CheckUpdates()
+	if res.Err != mod.ErrNoUpdate {
+		// Resolve page url for the summary
+		m.Page()
+	}
+	return
+}
+
 func Update() {
+	// Machine readable output only reports available updates
+	if config.Output != "text" {
+		mods, err := loadMods()
+		if err == nil {
+			_, err = printRecords(mods)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return
+	}
+
 	if runtime.GOOS != "linux" {
 		defer func() {
 			fmt.Print("Press any key to exit...")
@@ -25,7 +68,7 @@ func Update() {
 		}()
 	}
 
-	mods, err := mod.InfoFromPath(config.ModPath)
+	mods, err := loadMods()
 	if err != nil {
 		fmt.Println("Error loading mods:", err)
 		return
@@ -36,76 +79,27 @@ func Update() {
 		return
 	}
 
-	fmt.Println(":: Searching for updates...")
-
-	var (
-		updates     = make([]update, 0, len(mods))
-		preReleases = []update{} // pre-release mod version
-		unstable    = []update{} // pre-release game version
-		errors      = map[string]error{}
-		upToDate    = 0
-	)
-
-	for _, m := range mods {
-		if _, ignored := config.Ignored[m.ModID]; ignored {
-			fmt.Printf(" %s - Ignored\n", m.String())
-			continue
-		}
-
-		if m.Error != nil {
-			errors[m.Name] = m.Error
-			continue
-		}
-
-		u, err := m.CheckUpdates()
-		upd := update{m, u}
-
-		switch err {
-		case nil:
-			updates = append(updates, upd)
-
-		case mod.ErrNoUpdate:
-			upToDate += 1
-
-		case mod.ErrPreReleaseSkip:
-			preReleases = append(preReleases, upd)
-
-		case mod.ErrUnstableSkip:
-			unstable = append(unstable, upd)
+	run := backup.NewRun("update")
+	defer saveRun(run)
 
-		default:
-			errors[m.Name] = err
-		}
-	}
+	mods = fixDuplicates(mods, run)
 
-	if len(errors) > 0 {
-		fmt.Println(":: Errors encountered during check:")
-		for name, err := range errors {
-			fmt.Printf(" %s: %v\n", name, err)
-		}
-	}
-
-	if len(preReleases) > 0 {
-		fmt.Println(":: Pre-release updates skipped:")
-		for _, m := range preReleases {
-			fmt.Printf(" %s (%s -> %s) - %s\n", m.Name, m.Version, m.Update.Version, m.Page())
-		}
-	}
+	printGameVersion()
+	fmt.Println(":: Searching for updates...")
 
-	if len(unstable) > 0 {
-		fmt.Println(":: Unstable updates skipped:")
-		for _, m := range unstable {
-			fmt.Printf(" %s (%s -> %s) - %s\n", m.Name, m.Version, m.Update.Version, m.Page())
-		}
-	}
+	res := checkAll(mods)
+	res.Print()
 
-	fmt.Printf(":: %d updates available (%d are up to date).\n\n", len(updates), upToDate)
+	updates := res.updates
 	if len(updates) == 0 {
 		return
 	}
 
 	for i, m := range updates {
 		fmt.Printf("[%d] %s (%s -> %s) - %s\n", i+1, m.Name, m.Version, m.Update.Version, m.Page())
+		if config.Changelogs {
+			printChangelog(m.Info, m.Update.Version, "    ")
+		}
 	}
 
 	s := bufio.NewScanner(os.Stdin)
@@ -122,8 +116,32 @@ func Update() {
 		return
 	}
 
+	selected := slices.Collect(filter.Filter(OneBased(updates)))
+	if len(selected) == 0 {
+		return
+	}
+
+	// Release archives can't be inspected without network
+	if !config.Offline {
+		fmt.Println(":: Resolving dependencies...")
+		selected, err = resolveDependencies(mods, selected)
+		if err != nil {
+			for line := range strings.Lines(err.Error()) {
+				fmt.Print(" ", line)
+			}
+			fmt.Println("\n:: Update aborted, dependencies can't be satisfied")
+			return
+		}
+		fmt.Println()
+	}
+
 	fmt.Println(":: Updating mods...")
-	for m := range filter.Filter(OneBased(updates)) {
+	installUpdates(run, selected)
+}
+
+// installUpdates downloads selected updates, replaced files are recorded in the run
+func installUpdates(run *backup.Run, selected []update) {
+	for _, m := range selected {
 		fmt.Printf(" %s@%s", m.Name, m.Update.Version)
 
 		if config.DryRun {
@@ -131,16 +149,32 @@ func Update() {
 			continue
 		}
 
+		// New mod, nothing to replace
+		if m.Path == "" {
+			err := m.Update.Download()
+			if err != nil {
+				fmt.Println(" -", err)
+				continue
+			}
+			fmt.Println(" - OK")
+			run.Add(newEntry(m.Info, m.Update, ""))
+			continue
+		}
+
 		// Backup before download. New file might have the same filename
-		err = m.Backup()
+		oldPath := m.Path
+		dir, err := run.Dir()
+		if err == nil {
+			err = m.Backup(dir)
+		}
 		if err != nil {
-			fmt.Println("-", err)
+			fmt.Println(" -", err)
 			continue
 		}
 
 		err = m.Update.Download()
 		if err != nil {
-			fmt.Println("-", err)
+			fmt.Println(" -", err)
 
 			// Try to restore the backup
 			err = m.Restore()
@@ -150,11 +184,16 @@ func Update() {
 			continue
 		}
 
-		fmt.Println("- OK")
+		fmt.Println(" - OK")
+		keepDisabled(m.Info, m.Update)
 
+		entry := newEntry(m.Info, m.Update, oldPath)
 		if config.Backup {
+			entry.BackupPath = m.Path
+			run.Add(entry)
 			continue
 		}
+		run.Add(entry)
 
 		// Remove the backup
 		err = os.RemoveAll(m.Path)
@@ -165,6 +204,176 @@ func Update() {
 	}
 }
 
+// keepDisabled disables the installed update if the replaced mod was disabled.
+// Disabled list entries include the version, new version would be enabled otherwise.
+func keepDisabled(m *mod.Info, upd mod.Update) {
+	if !m.Disabled {
+		return
+	}
+
+	err := mod.DisableMods(&mod.Info{ModID: upd.ModID, Version: upd.Version})
+	if err != nil {
+		fmt.Println("Disable:", err)
+	}
+}
+
+// results groups update check results by outcome
+type results struct {
+	updates     []update
+	preReleases []update // pre-release mod version
+	unstable    []update // pre-release game version
+	newerGame   []update // unsupported game version
+	heldBack    []update // version constraint from config
+	errors      map[string]error
+	upToDate    int
+}
+
+// checkAll checks mods for updates, reporting ignored and disabled mods
+func checkAll(mods []*mod.Info) *results {
+	res := &results{
+		updates: make([]update, 0, len(mods)),
+		errors:  map[string]error{},
+	}
+
+	checks := Parallel(mods, checkMod)
+	for idx, m := range mods {
+		if _, ignored := config.Ignored[m.ModID]; ignored {
+			fmt.Printf(" %s - Ignored\n", m.String())
+			continue
+		}
+
+		if config.SkipDisabled && m.Disabled {
+			fmt.Printf(" %s - Disabled\n", m.String())
+			continue
+		}
+
+		if isClientOnly(m) {
+			fmt.Printf(" %s - Client-only, not updated on server\n", m.String())
+			continue
+		}
+
+		if m.Error != nil {
+			res.errors[m.Name] = m.Error
+			continue
+		}
+
+		u, err := checks[idx].Update, checks[idx].Err
+		upd := update{m, u}
+
+		switch err {
+		case nil:
+			res.updates = append(res.updates, upd)
+
+		case mod.ErrNoUpdate:
+			res.upToDate += 1
+
+		case mod.ErrPreReleaseSkip:
+			res.preReleases = append(res.preReleases, upd)
+
+		case mod.ErrUnstableSkip:
+			res.unstable = append(res.unstable, upd)
+
+		case mod.ErrGameVersionSkip:
+			res.newerGame = append(res.newerGame, upd)
+
+		case mod.ErrConstraintSkip:
+			res.heldBack = append(res.heldBack, upd)
+
+		default:
+			res.errors[m.Name] = err
+		}
+	}
+	return res
+}
+
+// Print prints errors, skipped updates and the summary line
+func (res *results) Print() {
+	if len(res.errors) > 0 {
+		fmt.Println(":: Errors encountered during check:")
+		for name, err := range res.errors {
+			fmt.Printf(" %s: %v\n", name, err)
+		}
+	}
+
+	if len(res.preReleases) > 0 {
+		fmt.Println(":: Pre-release updates skipped:")
+		for _, m := range res.preReleases {
+			fmt.Printf(" %s (%s -> %s) - %s\n", m.Name, m.Version, m.Update.Version, m.Page())
+		}
+	}
+
+	if len(res.unstable) > 0 {
+		fmt.Println(":: Unstable updates skipped:")
+		for _, m := range res.unstable {
+			fmt.Printf(" %s (%s -> %s) - %s\n", m.Name, m.Version, m.Update.Version, m.Page())
+		}
+	}
+
+	if len(res.newerGame) > 0 {
+		fmt.Printf(":: Updates incompatible with game %s skipped:\n", mod.GameVersion())
+		for _, m := range res.newerGame {
+			fmt.Printf(" %s (%s -> %s, requires game %s) - %s\n", m.Name, m.Version, m.Update.Version, m.Update.GameVersion, m.Page())
+		}
+	}
+
+	if len(res.heldBack) > 0 {
+		fmt.Println(":: Updates held back by constraint:")
+		for _, m := range res.heldBack {
+			fmt.Printf(" %s (%s -> %s, constraint %s) - %s\n", m.Name, m.Version, m.Update.Version, config.Mods[m.ModID].Version, m.Page())
+		}
+	}
+
+	fmt.Printf(":: %d updates available (%d are up to date).\n", len(res.updates), res.upToDate)
+	printRetries()
+	fmt.Println()
+}
+
+// printRetries reports ModDB requests that had to be retried
+func printRetries() {
+	if n := mod.Retries(); n > 0 {
+		fmt.Printf(":: %d ModDB requests retried\n", n)
+	}
+}
+
+// newEntry returns run record of the installed update, replacing file at oldPath
+func newEntry(m *mod.Info, upd mod.Update, oldPath string) backup.Entry {
+	e := backup.Entry{
+		ModID:      upd.ModID,
+		Name:       upd.Name,
+		OldPath:    oldPath,
+		NewVersion: upd.Version.String(),
+		NewPath:    upd.Path(),
+	}
+	if oldPath != "" {
+		e.OldVersion = m.Version.String()
+	}
+	return e
+}
+
+// resolveDependencies appends missing or outdated dependencies of the selected updates
+func resolveDependencies(installed []*mod.Info, selected []update) ([]update, error) {
+	updates := make([]mod.Update, 0, len(selected))
+	for _, m := range selected {
+		updates = append(updates, m.Update)
+	}
+
+	deps, err := mod.Resolve(installed, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dep := range deps {
+		fmt.Printf(" %s@%s - required by %s\n", dep.Name, dep.Version, dep.RequiredBy)
+
+		info := &mod.Info{ModID: dep.ModID, Name: dep.Name}
+		if i := slices.IndexFunc(installed, func(m *mod.Info) bool { return m.ModID == dep.ModID }); i >= 0 {
+			info = installed[i]
+		}
+		selected = append(selected, update{info, dep})
+	}
+	return selected, nil
+}
+
 func OneBased[T any](s []T) iter.Seq2[int, T] {
 	return func(yield func(int, T) bool) {
 		for i, v := range s {
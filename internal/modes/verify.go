@@ -0,0 +1,78 @@
+package modes
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rafalb8/VSModUpdater/v2/internal/backup"
+	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
+)
+
+// Verify compares installed mods with ModDB releases, with --redownload
+// modified mods are replaced by clean copies
+func Verify() {
+	mods, err := loadMods()
+	if err != nil {
+		fmt.Println("Error loading mods:", err)
+		return
+	}
+
+	if len(mods) == 0 {
+		fmt.Println("No Mods found")
+		return
+	}
+
+	type verified struct {
+		Update mod.Update
+		Err    error
+	}
+
+	fmt.Println(":: Verifying mods...")
+	results := Parallel(mods, func(m *mod.Info) (res verified) {
+		res.Update, res.Err = m.Verify()
+		return
+	})
+
+	var (
+		ok, problems, skipped int
+		modified              = []update{}
+	)
+	for idx, m := range mods {
+		name := m.String()
+		if m.Error != nil {
+			name = m.Path
+		}
+
+		err := results[idx].Err
+		switch {
+		case err == nil:
+			ok++
+			continue
+
+		case errors.Is(err, mod.ErrUnverifiable):
+			skipped++
+			fmt.Printf(" %s - skipped, %v\n", name, err)
+			continue
+
+		case errors.Is(err, mod.ErrModified):
+			modified = append(modified, update{m, results[idx].Update})
+		}
+
+		problems++
+		fmt.Printf(" %s - %v\n", name, err)
+	}
+	fmt.Printf(":: %d mods verified, %d with problems, %d skipped\n", ok, problems, skipped)
+	printRetries()
+	fmt.Println()
+
+	if len(modified) == 0 || !config.Redownload {
+		return
+	}
+
+	run := backup.NewRun("verify")
+	defer saveRun(run)
+
+	fmt.Println(":: Downloading clean copies...")
+	installUpdates(run, modified)
+}
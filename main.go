@@ -2,12 +2,17 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/rafalb8/VSModUpdater/v2/internal/config"
+	"github.com/rafalb8/VSModUpdater/v2/internal/mod"
 	"github.com/rafalb8/VSModUpdater/v2/internal/modes"
 )
 
 func main() {
+	// Release archives fetched while resolving dependencies of skipped updates
+	defer mod.RemoveFetched()
+
 	switch {
 	case config.Version:
 		fmt.Println(config.BuildVersion())
@@ -27,6 +32,36 @@ func main() {
 	case config.Export != "":
 		modes.Export(config.Export)
 
+	case config.Install:
+		modes.Install(config.Args)
+
+	case config.Uninstall:
+		modes.Uninstall(config.Args)
+
+	case config.Search != "":
+		modes.Search(config.Search)
+
+	case config.Changelog != "":
+		modes.Changelog(config.Changelog)
+
+	case config.Doctor:
+		modes.Doctor()
+
+	case config.Verify:
+		modes.Verify()
+
+	case config.SyncFrom != "":
+		modes.SyncFrom(config.SyncFrom)
+
+	case config.Check:
+		os.Exit(modes.Check())
+
+	case config.Rollback:
+		modes.Rollback()
+
+	case config.Prune:
+		modes.PruneBackups()
+
 	default:
 		modes.Update()
 	}